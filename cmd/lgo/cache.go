@@ -0,0 +1,41 @@
+// cmd/lgo/cache.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/jason-riddle/ledger-go/internal/shared"
+)
+
+// runCache implements `lgo cache prune`, removing cached extracted text.
+func runCache(args []string) int {
+	if len(args) == 0 || args[0] != "prune" {
+		fmt.Fprintf(os.Stderr, "Usage: lgo cache prune [--older-than DURATION] [--cache-dir DIR]\n")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("cache prune", flag.ContinueOnError)
+	cacheDir := fs.String("cache-dir", shared.DefaultCacheDir(), "Directory for cached extracted text")
+	olderThan := fs.Duration("older-than", 0, "Only remove entries unused for this long, e.g. 720h (0 removes everything)")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	setupLogging(*verbose)
+
+	if *cacheDir == "" {
+		fmt.Fprintf(os.Stderr, "Error: no cache directory; set --cache-dir\n")
+		return 1
+	}
+	cache := &shared.TextCache{Dir: *cacheDir}
+	removed, err := cache.Prune(*olderThan)
+	if err != nil {
+		slog.Error("Failed to prune text cache", "dir", *cacheDir, "error", err)
+		return 1
+	}
+	slog.Info("Pruned text cache", "dir", *cacheDir, "removed", removed)
+	return 0
+}
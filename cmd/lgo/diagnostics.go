@@ -0,0 +1,82 @@
+// cmd/lgo/diagnostics.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// diagnostic is one problem at a statement line, printed like a compiler
+// error.
+type diagnostic struct {
+	line    int
+	column  int
+	message string
+	text    string
+}
+
+// reportParseError prints a parse failure to stderr. Parse errors and
+// unrecognized lines are printed as diagnostics pointing into the statement
+// text; anything else is logged.
+func reportParseError(source string, err error) {
+	var diags []diagnostic
+	var parseErrs parser.ParseErrors
+	var unrecognized *parser.UnrecognizedError
+	switch {
+	case errors.As(err, &parseErrs):
+		for _, e := range parseErrs {
+			diags = append(diags, diagnostic{line: e.Line, column: e.Column, message: fmt.Sprintf("%v (%s)", e.Err, e.Institution), text: e.Text})
+		}
+	case errors.As(err, &unrecognized):
+		for _, line := range unrecognized.Lines {
+			diags = append(diags, diagnostic{line: line.Line, message: fmt.Sprintf("unrecognized line in %s (run lgo inspect to see how lines were classified)", line.Section), text: line.Text})
+		}
+	default:
+		slog.Error("Failed to parse statement", "error", err)
+		return
+	}
+	writeDiagnostics(os.Stderr, source, diags)
+}
+
+// writeDiagnostics prints each diagnostic as
+//
+//	statement.txt:148:62: error: invalid date "13-45-2025" (cloverleaf)
+//	  148 |   Rent - Rent (11-2025)   13-45-2025   $1,600.00   $0.00
+//	      |                           ^
+//
+// followed by the number of errors.
+func writeDiagnostics(w io.Writer, source string, diags []diagnostic) {
+	for _, d := range diags {
+		position := fmt.Sprintf("%s:%d", source, d.line)
+		if d.column > 0 {
+			position += fmt.Sprintf(":%d", d.column)
+		}
+		fmt.Fprintf(w, "%s: error: %s\n", position, d.message)
+
+		// Form feeds start pages; print them as spaces to keep columns.
+		text := strings.TrimRight(strings.ReplaceAll(d.text, "\f", " "), " \t\r")
+		gutter := fmt.Sprintf("%5d", d.line)
+		fmt.Fprintf(w, "%s | %s\n", gutter, text)
+		if d.column > 0 && d.column <= len(text)+1 {
+			// Keep tabs so the caret lines up under tab-indented text.
+			indent := strings.Map(func(r rune) rune {
+				if r == '\t' {
+					return r
+				}
+				return ' '
+			}, text[:d.column-1])
+			fmt.Fprintf(w, "%s | %s^\n", strings.Repeat(" ", len(gutter)), indent)
+		}
+	}
+	if len(diags) == 1 {
+		fmt.Fprintf(w, "1 error\n")
+	} else {
+		fmt.Fprintf(w, "%d errors\n", len(diags))
+	}
+}
@@ -0,0 +1,126 @@
+// cmd/lgo/export.go
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/export"
+	"github.com/jason-riddle/ledger-go/internal/shared"
+)
+
+// runExport implements `lgo export`, writing the parsed statement as JSON or CSV.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	pdfPath := fs.String("pdf-path", "", "Path to the PDF statement file to export")
+	textPath := fs.String("text-path", "", "Path to already-extracted statement text, or - for stdin")
+	institution := fs.String("institution", "cloverleaf", "Statement institution: "+strings.Join(institutionNames(), ", "))
+	extractArgs := registerExtractFlags(fs)
+	format := fs.String("format", "json", "Export format: json or csv")
+	output := fs.String("output", "", "File to write; defaults to stdout")
+	timeout := fs.Duration("timeout", 0, "Give up on extraction and parsing after this long, e.g. 30s (0 means no limit)")
+	strict := fs.Bool("strict", false, "Fail when a transaction section has lines no parser rule recognizes")
+	summaryCheck := fs.String("summary-check", "fail", "What to do when the statement's summary totals disagree with the entries: fail, warn or off")
+	payeesPath := fs.String("payees", shared.DefaultPayeesPath(), "Path to the JSON file of aliases mapping raw payees to canonical names")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	setupLogging(*verbose)
+
+	if (*pdfPath == "") == (*textPath == "") {
+		fmt.Fprintf(os.Stderr, "Error: exactly one of --pdf-path or --text-path is required\n")
+		fs.Usage()
+		return 1
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "Error: unknown export format %q (want json or csv)\n", *format)
+		fs.Usage()
+		return 1
+	}
+
+	summaryMode, err := shared.ParseSummaryCheck(*summaryCheck)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		return 1
+	}
+
+	extractOpts, err := extractArgs.options(*institution)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		return 1
+	}
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	source := outputSource(*pdfPath, *textPath, "", "")
+	txs, err := parseStatement(ctx, *pdfPath, *textPath, *institution, extractOpts, *strict, summaryMode)
+	if err != nil {
+		reportParseError(source, err)
+		return 1
+	}
+	if err := normalizePayees(txs, *payeesPath); err != nil {
+		slog.Error("Failed to read payees", "path", *payeesPath, "error", err)
+		return 1
+	}
+
+	var digest string
+	if *textPath != "-" {
+		digest, err = fileSHA256(source)
+		if err != nil {
+			slog.Error("Failed to hash statement", "path", source, "error", err)
+			return 1
+		}
+	}
+	doc := export.NewDocument(export.Statement{
+		Institution:  *institution,
+		Source:       source,
+		SourceSHA256: digest,
+	}, txs)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			slog.Error("Failed to create export file", "path", *output, "error", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "csv" {
+		err = export.WriteCSV(w, doc)
+	} else {
+		err = export.WriteJSON(w, doc)
+	}
+	if err != nil {
+		slog.Error("Failed to write export", "format", *format, "error", err)
+		return 1
+	}
+	slog.Info("Exported statement", "source", source, "format", *format, "entries", len(txs))
+	return 0
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,50 @@
+// cmd/lgo/extract.go
+package main
+
+import (
+	"flag"
+
+	"github.com/jason-riddle/ledger-go/internal/shared"
+)
+
+// extractFlags are the PDF extraction flags shared by lgo and its subcommands.
+type extractFlags struct {
+	extractor  *string
+	ocr        *bool
+	secretsDir *string
+	cacheDir   *string
+	noCache    *bool
+}
+
+// registerExtractFlags defines the extraction flags on fs.
+func registerExtractFlags(fs *flag.FlagSet) extractFlags {
+	return extractFlags{
+		extractor:  fs.String("extractor", "auto", "PDF text extractor: pdftotext, native or auto (pdftotext if installed)"),
+		ocr:        fs.Bool("ocr", false, "Recognize PDF pages without a text layer with tesseract; OCR'd transactions are flagged for review"),
+		secretsDir: fs.String("secrets-dir", shared.DefaultSecretsDir(), "Directory of per-institution <institution>.env files holding PDF passwords"),
+		cacheDir:   fs.String("cache-dir", shared.DefaultCacheDir(), "Directory for cached extracted text"),
+		noCache:    fs.Bool("no-cache", false, "Always extract text from the PDF, bypassing the text cache"),
+	}
+}
+
+// options builds extraction options from the flag values. The
+// institution's PDF passwords (see shared.LoadPasswords) are loaded only
+// when a PDF is extracted.
+func (f extractFlags) options(institution string) (shared.ExtractOptions, error) {
+	extractor, err := shared.ParseExtractor(*f.extractor)
+	if err != nil {
+		return shared.ExtractOptions{}, err
+	}
+	secretsDir := *f.secretsDir
+	opts := shared.ExtractOptions{
+		Extractor: extractor,
+		OCR:       *f.ocr,
+		PasswordLoader: func() (shared.Passwords, error) {
+			return shared.LoadPasswords(institution, secretsDir)
+		},
+	}
+	if !*f.noCache && *f.cacheDir != "" {
+		opts.Cache = &shared.TextCache{Dir: *f.cacheDir}
+	}
+	return opts, nil
+}
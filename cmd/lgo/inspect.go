@@ -0,0 +1,118 @@
+// cmd/lgo/inspect.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// runInspect implements `lgo inspect`, printing the statement text with each
+// line annotated by the parser rule that consumed or ignored it.
+func runInspect(args []string) int {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: lgo inspect [flags] FILE\n\nFILE is a PDF statement, extracted text, or - for text on stdin.\n\n")
+		fs.PrintDefaults()
+	}
+	institution := fs.String("institution", "cloverleaf", "Statement institution: "+strings.Join(institutionNames(), ", "))
+	extractArgs := registerExtractFlags(fs)
+	timeout := fs.Duration("timeout", 0, "Give up on extraction and parsing after this long, e.g. 30s (0 means no limit)")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	setupLogging(*verbose)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	newParser, err := lookupParser(*institution)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	extractOpts, err := extractArgs.options(*institution)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	pdfPath, textPath := "", fs.Arg(0)
+	if strings.EqualFold(filepath.Ext(textPath), ".pdf") {
+		pdfPath, textPath = textPath, ""
+	}
+	text, _, err := readStatement(ctx, pdfPath, textPath, extractOpts)
+	if err != nil {
+		slog.Error("Failed to read statement", "error", err)
+		return 1
+	}
+	return inspectStatement(ctx, os.Stdout, newParser(), text, outputSource(pdfPath, textPath, "", ""))
+}
+
+// inspectStatement parses text and writes the annotated report to w. When
+// parsing fails, the report of the lines read so far is still written
+// before the errors are reported as diagnostics against source. It returns
+// the exit code.
+func inspectStatement(ctx context.Context, w io.Writer, p parser.Parser, text, source string) int {
+	_, coverage, err := p.ParseCoverage(ctx, text)
+	if coverage != nil {
+		if err := writeInspectReport(w, coverage); err != nil {
+			slog.Error("Failed to write report", "error", err)
+			return 1
+		}
+	}
+	if err != nil {
+		reportParseError(source, err)
+		return 1
+	}
+	return 0
+}
+
+// writeInspectReport prints every line with its status and rule, then the
+// per-section totals and the unrecognized lines.
+func writeInspectReport(w io.Writer, coverage *parser.Coverage) error {
+	var b strings.Builder
+	for _, line := range coverage.Lines {
+		status, rule := line.Status.String(), line.Rule
+		if line.Status == parser.LineOutside {
+			status, rule = "", ""
+		}
+		text := strings.TrimRight(strings.ReplaceAll(line.Text, "\f", ""), " \t\r")
+		row := fmt.Sprintf("%5d  %-12s  %-20s | %s", line.Line, status, rule, text)
+		b.WriteString(strings.TrimRight(row, " "))
+		b.WriteByte('\n')
+	}
+
+	b.WriteString("\nSections:\n")
+	totals := coverage.Totals()
+	if len(totals) == 0 {
+		b.WriteString("  none found\n")
+	}
+	for _, t := range totals {
+		name := t.Section
+		if name == "" {
+			name = "(outside sections)"
+		}
+		fmt.Fprintf(&b, "  %s: %d lines, %d consumed, %d ignored, %d unrecognized\n", name, t.Lines(), t.Consumed, t.Ignored, t.Unrecognized)
+	}
+
+	unrecognized := coverage.Unrecognized()
+	fmt.Fprintf(&b, "\nUnmatched lines: %d\n", len(unrecognized))
+	for _, line := range unrecognized {
+		fmt.Fprintf(&b, "  line %d: %s\n", line.Line, strings.TrimSpace(line.Text))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,157 @@
+// cmd/lgo/interactive.go
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+	"github.com/jason-riddle/ledger-go/internal/shared"
+)
+
+// maxSuggestions is how many accounts the session offers per transaction.
+const maxSuggestions = 5
+
+// categorizeSession walks the user through each uncategorized transaction,
+// reading answers from in and writing prompts to out. Each transaction can
+// be booked to a suggested or named account, categorized with a new rule,
+// skipped, or the session ended. It returns the rules created; rules are
+// also applied to the remaining transactions they match. The session ends
+// early, keeping what was done, when in runs out.
+func categorizeSession(in io.Reader, out io.Writer, txs []*parser.Transaction, history *shared.History) ([]shared.Rule, error) {
+	var pending []*parser.Transaction
+	for _, tx := range txs {
+		if shared.Uncategorized(tx) {
+			pending = append(pending, tx)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	scanner := bufio.NewScanner(in)
+	// ask prompts and returns the trimmed answer, or false once in is done.
+	ask := func(prompt string) (string, bool) {
+		fmt.Fprint(out, prompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return "", false
+		}
+		return strings.TrimSpace(scanner.Text()), true
+	}
+
+	var rules []shared.Rule
+	for i, tx := range pending {
+		if !shared.Uncategorized(tx) {
+			// Categorized by a rule made earlier in the session.
+			continue
+		}
+		suggestions := history.Suggest(tx, maxSuggestions)
+		writeUncategorized(out, i+1, len(pending), tx, suggestions)
+		for done := false; !done; {
+			answer, ok := ask(fmt.Sprintf("Account [%s account name, r=rule, s=skip, q=quit]: ", choiceRange(len(suggestions))))
+			if !ok {
+				return rules, scanner.Err()
+			}
+			switch answer {
+			case "", "s":
+				done = true
+			case "q":
+				return rules, nil
+			case "r":
+				pattern, ok := ask(fmt.Sprintf("Narration pattern (regular expression; empty matches payee %q): ", tx.Payee))
+				if !ok {
+					return rules, scanner.Err()
+				}
+				answer, ok := ask(fmt.Sprintf("Rule account [%s account name]: ", choiceRange(len(suggestions))))
+				if !ok {
+					return rules, scanner.Err()
+				}
+				account, err := resolveAccount(answer, suggestions, history)
+				if err != nil {
+					fmt.Fprintf(out, "Invalid choice: %v.\n", err)
+					continue
+				}
+				payee := ""
+				if pattern == "" {
+					payee = tx.Payee
+				}
+				rule, err := shared.NewRule(payee, pattern, account)
+				if err != nil {
+					fmt.Fprintf(out, "Invalid rule: %v\n", err)
+					continue
+				}
+				if !rule.Matches(tx) {
+					fmt.Fprintf(out, "The rule does not match this transaction.\n")
+					continue
+				}
+				rules = append(rules, rule)
+				more := shared.ApplyRules(pending[i+1:], []shared.Rule{rule})
+				shared.Categorize(tx, account)
+				fmt.Fprintf(out, "Booked to %s; the rule also categorized %d more transaction(s).\n", account, more)
+				done = true
+			default:
+				account, err := resolveAccount(answer, suggestions, history)
+				if err != nil {
+					fmt.Fprintf(out, "Invalid choice: %v.\n", err)
+					continue
+				}
+				shared.Categorize(tx, account)
+				fmt.Fprintf(out, "Booked to %s.\n", account)
+				done = true
+			}
+		}
+	}
+	return rules, nil
+}
+
+// writeUncategorized describes transaction n of total and lists the
+// suggested accounts.
+func writeUncategorized(w io.Writer, n, total int, tx *parser.Transaction, suggestions []string) {
+	fmt.Fprintf(w, "\n[%d/%d] %s %s\n", n, total, tx.Date, tx.Payee)
+	if tx.Narration != "" && tx.Narration != tx.Payee {
+		fmt.Fprintf(w, "      %s\n", tx.Narration)
+	}
+	for _, p := range tx.Postings {
+		if p.Account == parser.SuspenseAccount {
+			fmt.Fprintf(w, "      %s %s\n", p.Amount.Value, p.Amount.Currency)
+		}
+	}
+	for i, account := range suggestions {
+		fmt.Fprintf(w, "  %d) %s\n", i+1, account)
+	}
+}
+
+// choiceRange describes the suggestion numbers, like "1-3,", or "" when
+// there are none.
+func choiceRange(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return "1,"
+	default:
+		return fmt.Sprintf("1-%d,", n)
+	}
+}
+
+// resolveAccount turns an answer into an account: a suggestion number or
+// an account name opened in the history.
+func resolveAccount(answer string, suggestions []string, history *shared.History) (string, error) {
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n < 1 || n > len(suggestions) {
+			return "", fmt.Errorf("no suggestion %d", n)
+		}
+		return suggestions[n-1], nil
+	}
+	if !strings.Contains(answer, ":") {
+		return "", fmt.Errorf("unrecognized choice %q", answer)
+	}
+	if !history.IsOpen(answer) {
+		return "", fmt.Errorf("account %s is not open in the ledger; open it or choose another", answer)
+	}
+	return answer, nil
+}
@@ -2,64 +2,458 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jason-riddle/ledger-go/internal/cloverleaf"
+	"github.com/jason-riddle/ledger-go/internal/export"
+	"github.com/jason-riddle/ledger-go/internal/parser"
 	"github.com/jason-riddle/ledger-go/internal/shared"
+	"github.com/jason-riddle/ledger-go/internal/sheervalue"
+	"github.com/jason-riddle/ledger-go/internal/sps"
 )
 
 var (
-	pdfPath   = flag.String("pdf-path", "", "Path to the PDF statement file to process")
-	outputDir = flag.String("output-dir", ".", "Directory to write generated .bean files")
-	verbose   = flag.Bool("verbose", false, "Enable verbose logging")
+	pdfPath       = flag.String("pdf-path", "", "Path to the PDF statement file to process")
+	textPath      = flag.String("text-path", "", "Path to already-extracted statement text, or - for stdin")
+	jsonPath      = flag.String("json-path", "", "Path to an lgo export JSON file to convert instead of a PDF")
+	outputName    = flag.String("output-name", "", "Base name for generated files (default: input file name, or stdin)")
+	institution   = flag.String("institution", "cloverleaf", "Statement institution: "+strings.Join(institutionNames(), ", "))
+	outputDir     = flag.String("output-dir", ".", "Directory to write generated .bean files")
+	order         = flag.String("order", "date", "Entry ordering in generated files: date or statement")
+	format        = flag.String("format", "beancount", "Output journal format: beancount, ledger or hledger")
+	timeout       = flag.Duration("timeout", 0, "Give up on extraction and parsing after this long, e.g. 30s (0 means no limit)")
+	strict        = flag.Bool("strict", false, "Fail when a transaction section has lines no parser rule recognizes")
+	summaryCheck  = flag.String("summary-check", "fail", "What to do when the statement's summary totals disagree with the entries: fail, warn or off")
+	suspense      = flag.String("suspense-account", parser.SuspenseAccount, "Account for amounts no account rule matches; such entries are flagged for review")
+	allocations   = flag.String("allocations", "", "Path to a JSON file of allocations splitting shared charges across properties")
+	payeesPath    = flag.String("payees", shared.DefaultPayeesPath(), "Path to the JSON file of aliases mapping raw payees to canonical names")
+	rulesPath     = flag.String("rules", shared.DefaultRulesPath(), "Path to the JSON file of rules categorizing unmatched entries")
+	interactive   = flag.Bool("interactive", false, "Ask on the terminal how to categorize each unmatched entry, saving new rules to --rules")
+	predict       = flag.Bool("predict", false, "Book unmatched entries to the account a classifier trained on --ledger predicts")
+	minConfidence = flag.Float64("min-confidence", 0.6, "Smallest confidence, from 0 to 1, at which --predict books an entry")
+	ledgerPath    = flag.String("ledger", "", "Path to an existing Beancount ledger whose entries suggest and predict accounts")
+	headerPath    = flag.String("header", "", "Path to the Beancount accounts header entries are checked against and whose open accounts --interactive offers")
+	missing       = flag.String("missing-accounts", "fail", "With --header, what to do about accounts it does not allow: fail, warn, or open to write the missing open directives")
+	verbose       = flag.Bool("verbose", false, "Enable verbose logging")
+	extractArgs   = registerExtractFlags(flag.CommandLine)
 )
 
+// parsers maps --institution values to parser constructors.
+var parsers = map[string]func() parser.Parser{
+	"cloverleaf": cloverleaf.NewParser,
+	"sheervalue": sheervalue.NewParser,
+	"sps":        sps.NewParser,
+}
+
 func main() {
-	flag.Parse()
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "cache":
+			os.Exit(runCache(os.Args[2:]))
+		case "inspect":
+			os.Exit(runInspect(os.Args[2:]))
+		case "review":
+			os.Exit(runReview(os.Args[2:]))
+		case "payees":
+			os.Exit(runPayees(os.Args[2:]))
+		case "import":
+			// "lgo import" is the default command spelled out.
+			args = args[1:]
+		}
+	}
 
-	// Setup logging
-	level := slog.LevelInfo
-	if *verbose {
-		level = slog.LevelDebug
+	flag.CommandLine.Parse(args)
+	setupLogging(*verbose)
+
+	if err := checkSingleInput(*pdfPath, *textPath, *jsonPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *interactive && *textPath == "-" {
+		fmt.Fprintf(os.Stderr, "Error: --interactive reads answers from stdin and cannot be used with --text-path -\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	entryOrder, err := shared.ParseOrder(*order)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	outputFormat, err := shared.ParseOutputFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
-	slog.SetDefault(logger)
 
-	if *pdfPath == "" {
-		fmt.Fprintf(os.Stderr, "Error: --pdf-path is required\n")
+	accountsMode, err := shared.ParseAccountsMode(*missing)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	// Extract text from PDF
-	text, err := shared.ExtractText(*pdfPath)
+	summaryMode, err := shared.ParseSummaryCheck(*summaryCheck)
 	if err != nil {
-		slog.Error("Failed to extract text", "error", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
 		os.Exit(1)
 	}
 
-	// Parse transactions
-	parser := cloverleaf.NewParser()
-	txs, err := parser.Parse(text)
+	extractOpts, err := extractArgs.options(*institution)
 	if err != nil {
-		slog.Error("Failed to parse transactions", "error", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	var txs []*parser.Transaction
+	source := outputSource(*pdfPath, *textPath, *jsonPath, *outputName)
+	if *jsonPath != "" {
+		// Convert a (possibly hand-edited) export back into entries
+		txs, err = readExport(*jsonPath)
+		if err != nil {
+			slog.Error("Failed to read export", "path", *jsonPath, "error", err)
+			os.Exit(1)
+		}
+	} else {
+		txs, err = parseStatement(ctx, *pdfPath, *textPath, *institution, extractOpts, *strict, summaryMode)
+		if err != nil {
+			reportParseError(source, err)
+			os.Exit(1)
+		}
+	}
+
+	if err := normalizePayees(txs, *payeesPath); err != nil {
+		slog.Error("Failed to read payees", "path", *payeesPath, "error", err)
 		os.Exit(1)
 	}
+	if err := categorize(txs); err != nil {
+		slog.Error("Categorization failed", "error", err)
+		os.Exit(1)
+	}
+	shared.RebookSuspense(txs, *suspense)
+	if *allocations != "" {
+		rules, err := shared.LoadAllocations(*allocations)
+		if err != nil {
+			slog.Error("Failed to read allocations", "path", *allocations, "error", err)
+			os.Exit(1)
+		}
+		if err := shared.Allocate(txs, rules); err != nil {
+			slog.Error("Allocation failed", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Validate transactions
 	if err := shared.ValidateTransactions(txs); err != nil {
 		slog.Error("Validation failed", "error", err)
 		os.Exit(1)
 	}
+	var opens []shared.OpenAccount
+	if *headerPath != "" {
+		accounts, err := shared.LoadAccountsHeader(*headerPath)
+		if err != nil {
+			slog.Error("Failed to read accounts header", "path", *headerPath, "error", err)
+			os.Exit(1)
+		}
+		if opens, err = checkAccounts(txs, accounts, accountsMode); err != nil {
+			slog.Error("Account validation failed", "header", *headerPath, "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Write output files
-	if err := shared.WriteBeanFiles(*outputDir, *pdfPath, txs); err != nil {
+	if err := shared.WriteBeanFiles(*outputDir, source, txs, shared.WriteOptions{Order: entryOrder, Format: outputFormat}); err != nil {
 		slog.Error("Failed to write files", "error", err)
 		os.Exit(1)
 	}
+	if len(opens) > 0 {
+		if err := writeOpensFile(*outputDir, source, opens, outputFormat); err != nil {
+			slog.Error("Failed to write files", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	slog.Info("Successfully processed statement", "source", source)
+}
+
+// checkAccounts checks txs against the accounts header. Each problem is
+// logged; in AccountsFail mode any problem is an error, in AccountsOpen mode
+// any problem an open directive cannot fix is. It returns the open
+// directives to write in AccountsOpen mode.
+func checkAccounts(txs []*parser.Transaction, accounts map[string]*shared.OpenAccount, mode shared.AccountsMode) ([]shared.OpenAccount, error) {
+	issues := shared.CheckAccounts(txs, accounts)
+	var errs []error
+	for _, issue := range issues {
+		if mode == shared.AccountsWarn || (mode == shared.AccountsOpen && issue.Unopened) {
+			slog.Warn("Account not allowed by header", "issue", issue.Error())
+			continue
+		}
+		slog.Error("Account not allowed by header", "issue", issue.Error())
+		errs = append(errs, issue)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%d entries use accounts the header does not allow: %w", len(errs), errors.Join(errs...))
+	}
+	if mode != shared.AccountsOpen {
+		return nil, nil
+	}
+	return shared.MissingOpens(issues), nil
+}
+
+// writeOpensFile writes the open directives for accounts the header lacks
+// next to the main journal file, for adding to the header.
+func writeOpensFile(outputDir, source string, opens []shared.OpenAccount, format shared.OutputFormat) error {
+	path := filepath.Join(outputDir, shared.OutputBaseName(source)+".accounts"+format.Extension())
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := shared.WriteOpens(file, opens, format); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	slog.Info("Wrote open directives for accounts missing from the header", "path", path, "accounts", len(opens))
+	return nil
+}
+
+// categorize books unmatched entries with the rules file, then with
+// --predict to accounts learned from the ledger, and with --interactive asks
+// on the terminal about the rest, saving any new rules for the next run.
+func categorize(txs []*parser.Transaction) error {
+	var rules []shared.Rule
+	if *rulesPath != "" {
+		var err error
+		if rules, err = shared.LoadRules(*rulesPath); err != nil {
+			return err
+		}
+		shared.ApplyRules(txs, rules)
+	}
+	if !*predict && !*interactive {
+		return nil
+	}
+	history, err := shared.LoadHistory(*ledgerPath, *headerPath)
+	if err != nil {
+		return err
+	}
+	if *predict {
+		if *ledgerPath == "" {
+			return fmt.Errorf("--predict needs a --ledger to learn from")
+		}
+		booked := shared.PredictAccounts(txs, shared.TrainClassifier(history), *minConfidence)
+		slog.Info("Predicted accounts", "categorized", booked)
+	}
+	if !*interactive {
+		return nil
+	}
+	added, err := categorizeSession(os.Stdin, os.Stdout, txs, history)
+	if err != nil || len(added) == 0 {
+		return err
+	}
+	if *rulesPath == "" {
+		return fmt.Errorf("no --rules file to save %d new rule(s) to", len(added))
+	}
+	if err := shared.SaveRules(*rulesPath, append(rules, added...)); err != nil {
+		return err
+	}
+	slog.Info("Saved new rules", "path", *rulesPath, "count", len(added))
+	return nil
+}
+
+func setupLogging(verbose bool) {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+}
+
+// checkSingleInput reports an error unless exactly one input path is set.
+func checkSingleInput(paths ...string) error {
+	count := 0
+	for _, path := range paths {
+		if path != "" {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("exactly one of --pdf-path, --text-path or --json-path is required")
+	}
+	return nil
+}
+
+// outputSource returns the path used to name generated files.
+func outputSource(pdfPath, textPath, jsonPath, outputName string) string {
+	switch {
+	case outputName != "":
+		return outputName
+	case textPath == "-":
+		return "stdin"
+	case textPath != "":
+		return textPath
+	case jsonPath != "":
+		return jsonPath
+	default:
+		return pdfPath
+	}
+}
+
+// commandContext returns a context cancelled on interrupt and, when timeout
+// is positive, after timeout has elapsed.
+func commandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// parseStatement reads the statement text, extracting it from the PDF unless
+// textPath is set, and runs the institution's parser over it. Extraction and
+// parsing stop once ctx is done. Transactions from OCR'd pages are flagged
+// for review. Lines in transaction sections that no parser rule recognizes
+// are logged, or returned as a *parser.UnrecognizedError when strict. When
+// the parser reads the statement's summary, its totals are checked against
+// the transactions as summaryCheck says; see checkSummary.
+func parseStatement(ctx context.Context, pdfPath, textPath, institution string, opts shared.ExtractOptions, strict bool, summaryCheck shared.SummaryCheck) ([]*parser.Transaction, error) {
+	newParser, err := lookupParser(institution)
+	if err != nil {
+		return nil, err
+	}
+	text, extraction, err := readStatement(ctx, pdfPath, textPath, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	slog.Info("Successfully processed statement", "pdf", *pdfPath)
+	p := newParser()
+	txs, coverage, err := p.ParseCoverage(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("parse transactions: %w", err)
+	}
+	if unrecognized := coverage.Unrecognized(); len(unrecognized) > 0 {
+		if strict {
+			return nil, coverage.Err()
+		}
+		for _, line := range unrecognized {
+			slog.Warn("Unrecognized statement line", "line", line.Line, "section", line.Section, "text", strings.TrimSpace(line.Text))
+		}
+	}
+	if summarizer, ok := p.(parser.Summarizer); ok && summaryCheck != shared.SummaryOff {
+		totals, err := summarizer.ParseSummary(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("parse summary: %w", err)
+		}
+		if err := checkSummary(txs, totals, summaryCheck); err != nil {
+			return nil, err
+		}
+	}
+	if extraction != nil {
+		shared.FlagOCRTransactions(txs, extraction)
+	}
+	return txs, nil
+}
+
+// checkSummary compares the summary totals with txs. Mismatches that
+// entries booked to suspense could explain are only logged, since
+// categorizing those entries resolves them; so is every mismatch with
+// shared.SummaryWarn. The rest are returned as a *shared.SummaryMismatchError.
+func checkSummary(txs []*parser.Transaction, totals []parser.SummaryTotal, summaryCheck shared.SummaryCheck) error {
+	err := shared.CheckSummary(txs, totals)
+	var mismatch *shared.SummaryMismatchError
+	if !errors.As(err, &mismatch) {
+		return err
+	}
+	var failed []shared.SummaryMismatch
+	for _, m := range mismatch.Mismatches {
+		switch {
+		case m.Suspense != "":
+			slog.Warn("Summary total differs by entries booked to suspense", "line", m.Total.Line, "label", m.Total.Label, "statement", m.Total.Amount, "entries", m.Got, "suspense", m.Suspense)
+		case summaryCheck == shared.SummaryWarn:
+			slog.Warn("Summary total disagrees with imported entries", "line", m.Total.Line, "label", m.Total.Label, "statement", m.Total.Amount, "entries", m.Got)
+		default:
+			failed = append(failed, m)
+		}
+	}
+	if len(failed) > 0 {
+		return &shared.SummaryMismatchError{Mismatches: failed}
+	}
+	return nil
+}
+
+// lookupParser returns the parser constructor for an --institution value.
+func lookupParser(institution string) (func() parser.Parser, error) {
+	newParser, ok := parsers[institution]
+	if !ok {
+		return nil, fmt.Errorf("unknown institution %q (want one of %s)", institution, strings.Join(institutionNames(), ", "))
+	}
+	return newParser, nil
+}
+
+// readStatement returns the statement text from textPath, or extracted from
+// pdfPath along with the extraction when textPath is empty.
+func readStatement(ctx context.Context, pdfPath, textPath string, opts shared.ExtractOptions) (string, *shared.Extraction, error) {
+	if textPath != "" {
+		text, err := shared.ReadText(textPath)
+		if err != nil {
+			return "", nil, fmt.Errorf("read text: %w", err)
+		}
+		return text, nil, nil
+	}
+	extraction, err := shared.ExtractContext(ctx, pdfPath, opts)
+	if err != nil {
+		return "", nil, fmt.Errorf("extract text: %w", err)
+	}
+	return extraction.Text, extraction, nil
+}
+
+func readExport(path string) ([]*parser.Transaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := export.ReadJSON(file)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Entries()
+}
+
+func institutionNames() []string {
+	names := make([]string, 0, len(parsers))
+	for name := range parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
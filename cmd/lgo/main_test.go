@@ -2,9 +2,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+	"github.com/jason-riddle/ledger-go/internal/shared"
 )
 
 func TestMain(t *testing.T) {
@@ -25,3 +33,512 @@ func TestMain(t *testing.T) {
 	// Test would require running the binary, but for unit test, skip
 	t.Skip("Integration test, run manually: lgo --pdf-path dummy.pdf --output-dir output")
 }
+
+func TestReadExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statement.json")
+	doc := `{"schema_version": 1, "statement": {"institution": "cloverleaf", "source": "statement.pdf"},
+  "transactions": [{"index": 1, "date": "2025-11-03", "payee": "Tenant", "postings": [
+    {"account": "Income:Rent", "amount": "-10.00", "currency": "USD"},
+    {"account": "Assets:Cash", "amount": "10.00", "currency": "USD"}]}],
+  "balances": [{"index": 0, "date": "2025-11-01", "account": "Assets:Cash", "amount": "5.00", "currency": "USD"}]}`
+	if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := readExport(path)
+	if err != nil {
+		t.Fatalf("readExport() error = %v", err)
+	}
+	if len(txs) != 2 || txs[0].Directive != "balance" || txs[1].Payee != "Tenant" {
+		t.Errorf("readExport() = %+v, want balance then Tenant transaction", txs)
+	}
+}
+
+func TestParseStatementFromText(t *testing.T) {
+	textPath := filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt")
+	txs, err := parseStatement(context.Background(), "", textPath, "cloverleaf", shared.ExtractOptions{}, false, shared.SummaryFail)
+	if err != nil {
+		t.Fatalf("parseStatement() error = %v", err)
+	}
+	if len(txs) != 14 {
+		t.Errorf("parseStatement() returned %d entries, want 14", len(txs))
+	}
+
+	if _, err := parseStatement(context.Background(), "", textPath, "unknown", shared.ExtractOptions{}, false, shared.SummaryFail); err == nil {
+		t.Errorf("Expected error for unknown institution")
+	}
+}
+
+func TestParseStatementCancelled(t *testing.T) {
+	textPath := filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, name := range institutionNames() {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseStatement(ctx, "", textPath, name, shared.ExtractOptions{}, false, shared.SummaryFail); !errors.Is(err, context.Canceled) {
+				t.Errorf("parseStatement() error = %v, want context canceled", err)
+			}
+		})
+	}
+}
+
+func TestParseStatementStrict(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Replace(string(fixture), "  Net $1,483.00", "  Pest Control - Quarterly   $95.00\n  Net $1,483.00", 1)
+	textPath := filepath.Join(t.TempDir(), "statement.txt")
+	if err := os.WriteFile(textPath, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parseStatement(context.Background(), "", textPath, "cloverleaf", shared.ExtractOptions{}, false, shared.SummaryFail); err != nil {
+		t.Fatalf("parseStatement() error = %v, want unrecognized lines only logged", err)
+	}
+	_, err = parseStatement(context.Background(), "", textPath, "cloverleaf", shared.ExtractOptions{}, true, shared.SummaryFail)
+	var unrecognized *parser.UnrecognizedError
+	if !errors.As(err, &unrecognized) || len(unrecognized.Lines) != 1 || unrecognized.Lines[0].Line != 143 {
+		t.Fatalf("strict parseStatement() error = %v, want line 143 unrecognized", err)
+	}
+	if !strings.Contains(err.Error(), "line 143: Pest Control - Quarterly") {
+		t.Errorf("error = %q, want the line number and text", err)
+	}
+}
+
+func TestParseStatementStrictSPS(t *testing.T) {
+	textPath := filepath.Join("..", "..", "tests", "fixtures", "sps", "sps_2023-11-14_mortgage.txt")
+	txs, err := parseStatement(context.Background(), "", textPath, "sps", shared.ExtractOptions{}, true, shared.SummaryFail)
+	if err != nil {
+		t.Fatalf("strict parseStatement() error = %v, want every activity line recognized", err)
+	}
+	var got []string
+	for _, tx := range txs {
+		if tx.Directive == "balance" {
+			continue
+		}
+		got = append(got, fmt.Sprintf("%d: %s %s %s %s", tx.Line, tx.Date, tx.Narration, tx.Postings[0].Account, tx.Postings[0].Amount.Value))
+	}
+	want := []string{
+		"55: 2023-10-16 Memo: Insurance Payment - Total: $702.88 Assets:Escrow:Taxes---Insurance:2943-Butterfly-Palm -702.88",
+		"56: 2023-11-01 Memo: Mortgage Payment - Principal: $65.47, Interest: $731.76, Escrow: $381.40 Equity:Owner-Contributions:Cash-Infusion -1178.63",
+		"57: 2023-11-02 Memo: Special Deposit Equity:Owner-Contributions:Cash-Infusion -1447.00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStatement() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestParseStatementSummaryMismatch(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Replace(string(fixture), "Total Expenses (-)                                 $902.70", "Total Expenses (-)                                 $992.70", 1)
+	textPath := filepath.Join(t.TempDir(), "statement.txt")
+	if err := os.WriteFile(textPath, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = parseStatement(context.Background(), "", textPath, "cloverleaf", shared.ExtractOptions{}, false, shared.SummaryFail)
+	var mismatch *shared.SummaryMismatchError
+	if !errors.As(err, &mismatch) || len(mismatch.Mismatches) != 1 {
+		t.Fatalf("parseStatement() error = %v, want one summary mismatch", err)
+	}
+	if got := mismatch.Mismatches[0]; got.Total.Line != 51 || got.Got != "902.70" {
+		t.Errorf("mismatch = %+v, want line 51 with entries 902.70", got)
+	}
+
+	for _, check := range []shared.SummaryCheck{shared.SummaryWarn, shared.SummaryOff} {
+		if _, err := parseStatement(context.Background(), "", textPath, "cloverleaf", shared.ExtractOptions{}, false, check); err != nil {
+			t.Errorf("parseStatement() with --summary-check=%s error = %v, want nil", check, err)
+		}
+	}
+}
+
+func TestCheckSummarySuspense(t *testing.T) {
+	txs := []*parser.Transaction{{Postings: []parser.Posting{
+		{Account: "Assets:PM", Amount: parser.Amount{Value: "-90.00", Currency: "USD"}},
+		{Account: parser.SuspenseAccount, Amount: parser.Amount{Value: "90.00", Currency: "USD"}},
+	}}}
+	explained := parser.SummaryTotal{Label: "Total Expenses", Account: "Expenses", Amount: "90.00", Line: 3}
+	unexplained := parser.SummaryTotal{Label: "Total Expenses", Account: "Expenses", Amount: "95.00", Line: 3}
+
+	if err := checkSummary(txs, []parser.SummaryTotal{explained}, shared.SummaryFail); err != nil {
+		t.Errorf("checkSummary() error = %v, want the suspense mismatch only logged", err)
+	}
+	var mismatch *shared.SummaryMismatchError
+	if err := checkSummary(txs, []parser.SummaryTotal{unexplained}, shared.SummaryFail); !errors.As(err, &mismatch) {
+		t.Errorf("checkSummary() error = %v, want a summary mismatch", err)
+	}
+	if err := checkSummary(txs, []parser.SummaryTotal{unexplained}, shared.SummaryWarn); err != nil {
+		t.Errorf("checkSummary() with warn error = %v, want nil", err)
+	}
+}
+
+func TestOutputSource(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		pdfPath, textPath, jsonPath, outName string
+		want                                 string
+	}{
+		{name: "pdf", pdfPath: "in/statement.pdf", want: "in/statement.pdf"},
+		{name: "text", textPath: "fixture.txt", want: "fixture.txt"},
+		{name: "stdin", textPath: "-", want: "stdin"},
+		{name: "json", jsonPath: "edited.json", want: "edited.json"},
+		{name: "override", textPath: "-", outName: "november", want: "november"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outputSource(tt.pdfPath, tt.textPath, tt.jsonPath, tt.outName); got != tt.want {
+				t.Errorf("outputSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := checkSingleInput("a.pdf", "b.txt", ""); err == nil {
+		t.Errorf("Expected error for multiple inputs")
+	}
+	if err := checkSingleInput("", "", ""); err == nil {
+		t.Errorf("Expected error for no inputs")
+	}
+}
+
+func TestRunCachePrune(t *testing.T) {
+	cacheDir := t.TempDir()
+	cache := &shared.TextCache{Dir: cacheDir}
+	if err := cache.Put("key", "statement.pdf", &shared.Extraction{Text: "Owner Statement"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := runCache(nil); code != 2 {
+		t.Errorf("runCache() without a subcommand = %d, want 2", code)
+	}
+	if code := runCache([]string{"prune", "--cache-dir", cacheDir, "--older-than", "24h"}); code != 0 {
+		t.Fatalf("runCache(prune --older-than 24h) = %d, want 0", code)
+	}
+	if cache.Get("key") == nil {
+		t.Errorf("prune --older-than 24h removed a fresh entry")
+	}
+	if code := runCache([]string{"prune", "--cache-dir", cacheDir}); code != 0 {
+		t.Fatalf("runCache(prune) = %d, want 0", code)
+	}
+	if cache.Get("key") != nil {
+		t.Errorf("prune left the entry in place")
+	}
+}
+
+func TestWriteInspectReport(t *testing.T) {
+	coverage := &parser.Coverage{}
+	coverage.Add(1, "Owner Statement", "", parser.LineOutside, "")
+	coverage.Add(2, "\fTRANSACTION DETAILS", "TRANSACTION DETAILS", parser.LineIgnored, "section heading")
+	coverage.Add(3, "  Rent   11-01-2025   $1,600.00   $0.00", "TRANSACTION DETAILS", parser.LineConsumed, "transaction")
+	coverage.Add(4, "  Pest Control   $95.00  ", "TRANSACTION DETAILS", parser.LineUnrecognized, "")
+
+	var b strings.Builder
+	if err := writeInspectReport(&b, coverage); err != nil {
+		t.Fatal(err)
+	}
+	want := `    1                                     | Owner Statement
+    2  ignored       section heading      | TRANSACTION DETAILS
+    3  consumed      transaction          |   Rent   11-01-2025   $1,600.00   $0.00
+    4  unrecognized                       |   Pest Control   $95.00
+
+Sections:
+  TRANSACTION DETAILS: 3 lines, 1 consumed, 1 ignored, 1 unrecognized
+
+Unmatched lines: 1
+  line 4: Pest Control   $95.00
+`
+	if b.String() != want {
+		t.Errorf("writeInspectReport() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	if code := runInspect(nil); code != 2 {
+		t.Errorf("runInspect() without a file = %d, want 2", code)
+	}
+}
+
+func TestInspectStatementParseError(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Replace(string(fixture), "11-05-2025", "11-35-2025", 1)
+	newParser, err := lookupParser("cloverleaf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if code := inspectStatement(context.Background(), &b, newParser(), text, "statement.txt"); code != 1 {
+		t.Errorf("inspectStatement() = %d, want 1", code)
+	}
+	// The report is still written, with the failed line annotated.
+	for _, want := range []string{"  156  unrecognized  parse error", "Unmatched lines: 1\n  line 156: "} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report does not contain %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestWriteReviewReport(t *testing.T) {
+	entries := []shared.ReviewEntry{
+		{Path: "2025/cloverleaf.bean", Line: 12, Date: "2025-11-20", Description: `"Pest Control" "Memo: Pest Control"`, Reason: "no account rule matches the description"},
+		{Path: "main.bean", Line: 40, Date: "2025-01-06", Description: `"Bank" "Wire fee"`},
+	}
+	var b strings.Builder
+	if err := writeReviewReport(&b, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := `2025/cloverleaf.bean:12: 2025-11-20 "Pest Control" "Memo: Pest Control"
+  review: no account rule matches the description
+main.bean:40: 2025-01-06 "Bank" "Wire fee"
+  review: (no reason given)
+2 transaction(s) flagged for review
+`
+	if b.String() != want {
+		t.Errorf("writeReviewReport() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	ledger := filepath.Join(t.TempDir(), "main.bean")
+	if err := os.WriteFile(ledger, []byte("2025-01-06 ! \"Bank\" \"Wire fee\"\n  Expenses:Unidentified  15.00 USD\n  Assets:Cash\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no ledger", want: 2},
+		{name: "flagged", args: []string{ledger}, want: 0},
+		{name: "flagged with check", args: []string{"--check", ledger}, want: 1},
+		{name: "missing ledger", args: []string{filepath.Join(t.TempDir(), "missing.bean")}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := runReview(tt.args); code != tt.want {
+				t.Errorf("runReview(%q) = %d, want %d", tt.args, code, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategorizeSession(t *testing.T) {
+	unmatched := func(payee, narration string) *parser.Transaction {
+		tx := &parser.Transaction{Date: "2025-11-20", Payee: payee, Narration: narration, Postings: []parser.Posting{
+			{Account: "Assets:PM", Amount: parser.Amount{Value: "-95.00", Currency: "USD"}},
+			{Account: parser.SuspenseAccount, Amount: parser.Amount{Value: "95.00", Currency: "USD"}},
+		}}
+		tx.MarkForReview(parser.UnmatchedReason)
+		return tx
+	}
+	history := &shared.History{
+		Entries: []shared.HistoryEntry{{Payee: "Pest Control", Narration: "Quarterly service", Postings: []parser.Posting{{Account: "Expenses:Pest-Control"}, {Account: "Assets:PM"}}}},
+		Open:    []string{"Expenses:Pest-Control", "Expenses:Landscaping", "Assets:PM"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		want     []string
+		wantRule []shared.Rule
+		// wantOut lists lines the session must print.
+		wantOut []string
+	}{
+		{
+			name:  "suggestion number",
+			input: "1\n",
+			want:  []string{"Expenses:Pest-Control", parser.SuspenseAccount, parser.SuspenseAccount},
+		},
+		{
+			name:  "account name after unopened and unknown answers",
+			input: "Expenses:Pool\n9\nlawn\nExpenses:Landscaping\ns\nq\n",
+			want:  []string{"Expenses:Landscaping", parser.SuspenseAccount, parser.SuspenseAccount},
+			wantOut: []string{
+				"Invalid choice: account Expenses:Pool is not open in the ledger; open it or choose another.\n",
+				"Invalid choice: no suggestion 9.\n",
+				"Invalid choice: unrecognized choice \"lawn\".\n",
+			},
+		},
+		{
+			name:     "payee rule applies to later entries",
+			input:    "r\n\n1\n",
+			want:     []string{"Expenses:Pest-Control", "Expenses:Pest-Control", parser.SuspenseAccount},
+			wantRule: []shared.Rule{{Matcher: shared.Matcher{Payee: "Pest Control"}, Account: "Expenses:Pest-Control"}},
+		},
+		{
+			name:     "narration rule",
+			input:    "s\ns\nr\n(?i)mowing\nExpenses:Landscaping\n",
+			want:     []string{parser.SuspenseAccount, parser.SuspenseAccount, "Expenses:Landscaping"},
+			wantRule: []shared.Rule{{Matcher: shared.Matcher{Narration: "(?i)mowing"}, Account: "Expenses:Landscaping"}},
+		},
+		{
+			name:  "rule that does not match is rejected",
+			input: "r\nRoof\n1\n",
+			want:  []string{parser.SuspenseAccount, parser.SuspenseAccount, parser.SuspenseAccount},
+		},
+		{
+			name:  "end of input",
+			input: "",
+			want:  []string{parser.SuspenseAccount, parser.SuspenseAccount, parser.SuspenseAccount},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txs := []*parser.Transaction{
+				unmatched("Pest Control", "Memo: Pest Control"),
+				unmatched("Pest Control", "Memo: Follow-up visit"),
+				unmatched("Green Yards", "Memo: Mowing"),
+			}
+			var out strings.Builder
+			rules, err := categorizeSession(strings.NewReader(tt.input), &out, txs, history)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, tx := range txs {
+				got = append(got, tx.Postings[1].Account)
+				if categorized := tx.Postings[1].Account != parser.SuspenseAccount; categorized == (tx.Flag == parser.FlagReview) {
+					t.Errorf("%s: flag = %q after booking to %s", tx.Narration, tx.Flag, tx.Postings[1].Account)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("accounts = %q, want %q\nsession:\n%s", got, tt.want, out.String())
+			}
+			var gotRules []shared.Rule
+			for _, rule := range rules {
+				gotRules = append(gotRules, shared.Rule{Matcher: shared.Matcher{Payee: rule.Payee, Narration: rule.Narration}, Account: rule.Account})
+			}
+			if !reflect.DeepEqual(gotRules, tt.wantRule) {
+				t.Errorf("rules = %+v, want %+v", gotRules, tt.wantRule)
+			}
+			for _, line := range tt.wantOut {
+				if !strings.Contains(out.String(), line) {
+					t.Errorf("session does not print %q:\n%s", line, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWritePayeesReport(t *testing.T) {
+	payees := []shared.RawPayee{
+		{Payee: "Contractor", Count: 12},
+		{Payee: "CloverLeaf Property Management", Count: 3, Canonical: "CloverLeaf"},
+	}
+	var b strings.Builder
+	if err := writePayeesReport(&b, payees); err != nil {
+		t.Fatal(err)
+	}
+	want := `   12  Contractor
+    3  CloverLeaf Property Management -> CloverLeaf
+2 payee(s) not normalized
+`
+	if b.String() != want {
+		t.Errorf("writePayeesReport() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	if code := runPayees(nil); code != 2 {
+		t.Errorf("runPayees() with no ledger = %d, want 2", code)
+	}
+}
+
+func TestExportNormalizesPayees(t *testing.T) {
+	dir := t.TempDir()
+	payeesPath := filepath.Join(dir, "payees.json")
+	if err := os.WriteFile(payeesPath, []byte(`{"payees": [{"name": "CloverLeaf", "raw": ["CloverLeaf Property Management"]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "export.json")
+	textPath := filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt")
+
+	if code := runExport([]string{"--text-path", textPath, "--payees", payeesPath, "--output", output}); code != 0 {
+		t.Fatalf("runExport() = %d, want 0", code)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"payee": "CloverLeaf"`) || strings.Contains(string(data), "CloverLeaf Property Management") {
+		t.Errorf("export = %s, want payees normalized to CloverLeaf", data)
+	}
+}
+
+func TestCheckAccounts(t *testing.T) {
+	accounts := map[string]*shared.OpenAccount{
+		"Assets:PM":        {Account: "Assets:PM", Opened: "1970-01-01"},
+		"Expenses:Repairs": {Account: "Expenses:Repairs", Opened: "2025-06-01"},
+	}
+	usd := func(value string) parser.Amount { return parser.Amount{Value: value, Currency: "USD"} }
+	unopened := &parser.Transaction{Line: 7, Date: "2025-03-01", Postings: []parser.Posting{
+		{Account: "Assets:PM", Amount: usd("-25.00")}, {Account: "Income:Pet-Fee:206-Hoover-Ave", Amount: usd("25.00")},
+	}}
+	early := &parser.Transaction{Line: 9, Date: "2025-03-02", Postings: []parser.Posting{
+		{Account: "Assets:PM", Amount: usd("-50.00")}, {Account: "Expenses:Repairs", Amount: usd("50.00")},
+	}}
+
+	tests := []struct {
+		name      string
+		txs       []*parser.Transaction
+		mode      shared.AccountsMode
+		wantOpens int
+		wantErr   string
+	}{
+		{name: "fail", txs: []*parser.Transaction{unopened}, mode: shared.AccountsFail, wantErr: "1 entries use accounts the header does not allow"},
+		{name: "warn", txs: []*parser.Transaction{unopened, early}, mode: shared.AccountsWarn},
+		{name: "open", txs: []*parser.Transaction{unopened}, mode: shared.AccountsOpen, wantOpens: 1},
+		{name: "open cannot fix an early entry", txs: []*parser.Transaction{unopened, early}, mode: shared.AccountsOpen, wantErr: "account opens on 2025-06-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opens, err := checkAccounts(tt.txs, accounts, tt.mode)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("checkAccounts() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(opens) != tt.wantOpens {
+				t.Errorf("checkAccounts() = %d opens, want %d", len(opens), tt.wantOpens)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	opens := []shared.OpenAccount{{Account: "Income:Pet-Fee:206-Hoover-Ave", Opened: "2025-03-01", Currencies: []string{"USD"}}}
+	if err := writeOpensFile(dir, "statements/cloverleaf.pdf", opens, shared.OutputBeancount); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "cloverleaf.accounts.bean"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2025-03-01 open Income:Pet-Fee:206-Hoover-Ave USD\n"; string(data) != want {
+		t.Errorf("accounts file = %q, want %q", data, want)
+	}
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	diags := []diagnostic{
+		{line: 148, column: 11, message: `invalid date "11-35-2025" (cloverleaf)`, text: "\f Rent\t   11-35-2025   $1,600.00  "},
+		{line: 150, message: "unrecognized line in TRANSACTION DETAILS", text: "  Pest Control   $95.00"},
+	}
+
+	var b strings.Builder
+	writeDiagnostics(&b, "statement.txt", diags)
+	want := "statement.txt:148:11: error: invalid date \"11-35-2025\" (cloverleaf)\n" +
+		"  148 |   Rent\t   11-35-2025   $1,600.00\n" +
+		"      |       \t   ^\n" +
+		"statement.txt:150: error: unrecognized line in TRANSACTION DETAILS\n" +
+		"  150 |   Pest Control   $95.00\n" +
+		"2 errors\n"
+	if b.String() != want {
+		t.Errorf("writeDiagnostics() =\n%q\nwant\n%q", b.String(), want)
+	}
+}
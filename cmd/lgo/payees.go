@@ -0,0 +1,84 @@
+// cmd/lgo/payees.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+	"github.com/jason-riddle/ledger-go/internal/shared"
+)
+
+// normalizePayees renames the payees in txs that an alias in the payee
+// alias file at path matches. An empty path applies no aliases.
+func normalizePayees(txs []*parser.Transaction, path string) error {
+	if path == "" {
+		return nil
+	}
+	aliases, err := shared.LoadPayees(path)
+	if err != nil {
+		return err
+	}
+	shared.NormalizePayees(txs, aliases)
+	return nil
+}
+
+// runPayees implements `lgo payees`, listing the payees in one or more
+// Beancount ledgers that are not yet normalized to a canonical name.
+func runPayees(args []string) int {
+	fs := flag.NewFlagSet("payees", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: lgo payees [flags] LEDGER...\n\nLEDGER is a Beancount file; included files are read too.\n\n")
+		fs.PrintDefaults()
+	}
+	payeesPath := fs.String("payees", shared.DefaultPayeesPath(), "Path to the JSON file of payee aliases")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	setupLogging(*verbose)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	var aliases []shared.PayeeAlias
+	if *payeesPath != "" {
+		var err error
+		if aliases, err = shared.LoadPayees(*payeesPath); err != nil {
+			slog.Error("Failed to read payees", "path", *payeesPath, "error", err)
+			return 1
+		}
+	}
+	history, err := shared.LoadHistory(fs.Args()...)
+	if err != nil {
+		slog.Error("Failed to read ledger", "error", err)
+		return 1
+	}
+
+	if err := writePayeesReport(os.Stdout, shared.UnnormalizedPayees(history, aliases)); err != nil {
+		slog.Error("Failed to write report", "error", err)
+		return 1
+	}
+	return 0
+}
+
+// writePayeesReport prints each raw payee with its transaction count and
+// the canonical name an alias already gives it, then the count.
+func writePayeesReport(w io.Writer, payees []shared.RawPayee) error {
+	var b strings.Builder
+	for _, payee := range payees {
+		fmt.Fprintf(&b, "%5d  %s", payee.Count, payee.Payee)
+		if payee.Canonical != "" {
+			fmt.Fprintf(&b, " -> %s", payee.Canonical)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d payee(s) not normalized\n", len(payees))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,70 @@
+// cmd/lgo/review.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+	"github.com/jason-riddle/ledger-go/internal/shared"
+)
+
+// runReview implements `lgo review`, listing the transactions flagged for
+// review in one or more ledger files and the files they include.
+func runReview(args []string) int {
+	fs := flag.NewFlagSet("review", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: lgo review [flags] LEDGER...\n\nLEDGER is a Beancount, ledger or hledger file; included files are read too.\n\n")
+		fs.PrintDefaults()
+	}
+	check := fs.Bool("check", false, "Exit with status 1 when any transaction is flagged for review")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	setupLogging(*verbose)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	var entries []shared.ReviewEntry
+	for _, path := range fs.Args() {
+		found, err := shared.FindReviews(path)
+		if err != nil {
+			slog.Error("Failed to read ledger", "path", path, "error", err)
+			return 1
+		}
+		entries = append(entries, found...)
+	}
+
+	if err := writeReviewReport(os.Stdout, entries); err != nil {
+		slog.Error("Failed to write report", "error", err)
+		return 1
+	}
+	if *check && len(entries) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeReviewReport prints each flagged transaction with its location and
+// review reason, then the count.
+func writeReviewReport(w io.Writer, entries []shared.ReviewEntry) error {
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "%s:%d: %s %s\n", entry.Path, entry.Line, entry.Date, entry.Description)
+		reason := entry.Reason
+		if reason == "" {
+			reason = "(no reason given)"
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", parser.ReviewKey, reason)
+	}
+	fmt.Fprintf(&b, "%d transaction(s) flagged for review\n", len(entries))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
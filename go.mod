@@ -1,7 +1,10 @@
 module github.com/jason-riddle/ledger-go
 
-go 1.24.0
+go 1.24.1
 
-require github.com/robinvdvleuten/beancount v0.6.1-0.20251224120644-bb390fe678a7
+require (
+	github.com/ledongthuc/pdf v0.0.0-20250511090121-5959a4027728
+	github.com/robinvdvleuten/beancount v0.6.1-0.20251224120644-bb390fe678a7
+)
 
 require golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
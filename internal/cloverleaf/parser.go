@@ -2,11 +2,14 @@
 package cloverleaf
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"regexp"
 	"strings"
 
+	"github.com/jason-riddle/ledger-go/internal/layout"
+	"github.com/jason-riddle/ledger-go/internal/normalize"
 	"github.com/jason-riddle/ledger-go/internal/parser"
 )
 
@@ -19,8 +22,39 @@ func NewParser() parser.Parser {
 
 // Parse extracts transactions from CloverLeaf statement text.
 func (p *cloverLeafParser) Parse(text string) ([]*parser.Transaction, error) {
+	return p.ParseContext(context.Background(), text)
+}
+
+// ParseContext extracts transactions from CloverLeaf statement text, checking ctx
+// before each line.
+func (p *cloverLeafParser) ParseContext(ctx context.Context, text string) ([]*parser.Transaction, error) {
+	txs, _, err := p.ParseCoverage(ctx, text)
+	return txs, err
+}
+
+// institution names the parser in errors.
+const institution = "cloverleaf"
+
+// detailsSection is the section transactions are read from.
+const detailsSection = "TRANSACTION DETAILS"
+
+var (
+	subtotalRe   = regexp.MustCompile(`^\s*(Statement\s+)?Net\b`)
+	pageFooterRe = regexp.MustCompile(`^\s*\d+ of \d+\s*$`)
+)
+
+// ParseCoverage is ParseContext that also classifies every line inside
+// TRANSACTION DETAILS, OPEN WORK ORDERS and MANAGED UNITS as consumed,
+// ignored or unrecognized.
+//
+// The reserve printed after each property's address and the deposits held
+// for each managed unit are asserted as balances on the statement end date
+// when the statement posts to the account (see parser.DropUnpostedHoldings).
+func (p *cloverLeafParser) ParseCoverage(ctx context.Context, text string) ([]*parser.Transaction, *parser.Coverage, error) {
 	slog.Debug("Starting CloverLeaf parsing", "text_length", len(text))
 	var txs []*parser.Transaction
+	var errs parser.ParseErrors
+	coverage := &parser.Coverage{}
 
 	periodRe := regexp.MustCompile(`Statement Period\s+(\d{2}-\d{2}-\d{4})\s+to\s+(\d{2}-\d{2}-\d{4})`)
 	periodLineRe := regexp.MustCompile(`(\d{2}-\d{2}-\d{4})\s+to\s+(\d{2}-\d{2}-\d{4})`)
@@ -30,86 +64,275 @@ func (p *cloverLeafParser) Parse(text string) ([]*parser.Transaction, error) {
 	// Regex for transaction lines: desc date increase decrease
 	re := regexp.MustCompile(`(.+?)\s+(\d{2}-\d{2}-\d{4})\s+[\$]?([\d,]+\.\d{2}|0\.00)\s+[\$]?([\d,]+\.\d{2}|0\.00)`)
 	lines := strings.Split(text, "\n")
+	rows := layout.TextLines(text)
 	var currentProperty string
 	var matches int
 	var statementEndDate string
 	inDetails := false
 	addedEndingBalance := false
-	for _, line := range lines {
+	// memoLines holds the indexes of continuation lines already joined
+	// into a transaction's memo.
+	memoLines := map[int]bool{}
+	inWorkOrders := false
+	// orderColumns is the work order table layout from its latest header,
+	// and orderLines holds the indexes of lines merged into a work order.
+	var orderColumns []layout.Column
+	orderLines := map[int]bool{}
+	inUnits := false
+	// unitColumns is the managed units table layout from its header.
+	var unitCols []layout.Column
+	for lineIdx, line := range lines {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		section := ""
+		if inDetails {
+			section = detailsSection
+		} else if inWorkOrders {
+			section = workOrdersSection
+		} else if inUnits {
+			section = unitsSection
+		}
+		note := func(status parser.LineStatus, rule string) {
+			coverage.Add(lineIdx+1, line, section, status, rule)
+		}
+		// fail records a field of this line that could not be converted.
+		fail := func(field string, err error) {
+			errs = append(errs, parser.NewParseError(institution, lineIdx+1, line, field, err))
+			note(parser.LineUnrecognized, "parse error")
+		}
+
 		if statementEndDate == "" {
-			if periodMatch := periodRe.FindStringSubmatch(line); periodMatch != nil {
-				statementEndDate = formatDateDash(periodMatch[2])
-			} else if periodLineMatch := periodLineRe.FindStringSubmatch(line); periodLineMatch != nil {
-				statementEndDate = formatDateDash(periodLineMatch[2])
+			periodMatch := periodRe.FindStringSubmatch(line)
+			if periodMatch == nil {
+				periodMatch = periodLineRe.FindStringSubmatch(line)
+			}
+			if periodMatch != nil {
+				date, err := normalize.Date(periodMatch[2])
+				if err != nil {
+					errs = append(errs, parser.NewParseError(institution, lineIdx+1, line, periodMatch[2], err))
+				}
+				statementEndDate = date
 			}
 		}
-		if strings.Contains(line, "TRANSACTION DETAILS") {
+		if strings.Contains(line, detailsSection) {
 			inDetails = true
+			section = detailsSection
+			note(parser.LineIgnored, "section heading")
 			continue
 		}
-		if strings.Contains(line, "OPEN WORK ORDERS") {
+		if strings.Contains(line, workOrdersSection) {
 			inDetails = false
+			inWorkOrders = true
+			section = workOrdersSection
+			note(parser.LineIgnored, "section heading")
+			continue
+		}
+		if strings.TrimSpace(line) == unitsSection {
+			inDetails, inWorkOrders = false, false
+			inUnits = true
+			section = unitsSection
+			note(parser.LineIgnored, "section heading")
+			continue
+		}
+		if (inWorkOrders || inUnits) && sectionHeadingRe.MatchString(line) {
+			inWorkOrders, inUnits = false, false
+			section = ""
+		}
+		if inUnits {
+			if header := unitColumns(rows[lineIdx]); header != nil {
+				unitCols = header
+				note(parser.LineIgnored, "column header")
+				continue
+			}
+			cells := rows[lineIdx].CellsByName(unitCols)
+			if !isUnitRow(cells) {
+				note(classifyUnitLine(line))
+				continue
+			}
+			if statementEndDate == "" {
+				note(parser.LineIgnored, "deposit without statement date")
+				continue
+			}
+			balance, err := depositBalance(cells, statementEndDate, lineIdx+1)
+			if err != nil {
+				fail(cells["Deposit Held"], err)
+				continue
+			}
+			txs = append(txs, balance)
+			note(parser.LineConsumed, "deposit balance")
+			continue
+		}
+		if inWorkOrders {
+			if orderLines[lineIdx] {
+				note(parser.LineConsumed, "work order text")
+				continue
+			}
+			if header := workOrderColumns(rows[lineIdx]); header != nil {
+				orderColumns = header
+				note(parser.LineIgnored, "column header")
+				continue
+			}
+			cells := rows[lineIdx].CellsByName(orderColumns)
+			if !isWorkOrderRow(cells) {
+				note(classifyWorkOrderLine(line))
+				continue
+			}
+			// The description may follow the row, in paragraphs separated by
+			// blank lines.
+			for next := lineIdx + 1; next < len(lines); next++ {
+				if strings.TrimSpace(lines[next]) == "" {
+					continue
+				}
+				more := rows[next].CellsByName(orderColumns)
+				if !isWorkOrderText(lines[next], more) {
+					break
+				}
+				for name, text := range more {
+					if cells[name] == "" {
+						cells[name] = text
+					} else {
+						cells[name] = joinWrapped(cells[name], text)
+					}
+				}
+				orderLines[next] = true
+			}
+			if strings.TrimSpace(cells["Location"]) == "" {
+				slog.Warn("Skipping work order without a location", "work_order", cells["Work Order #"], "line", lineIdx+1)
+				note(parser.LineIgnored, "work order without a location")
+				continue
+			}
+			dateStr, err := normalize.Date(cells["Date"])
+			if err != nil {
+				fail(cells["Date"], err)
+				continue
+			}
+			var estimate string
+			if cells["Estimate"] != "" {
+				if estimate, err = normalize.Amount(cells["Estimate"]); err != nil {
+					fail(cells["Estimate"], err)
+					continue
+				}
+			}
+			txs = append(txs, workOrderNote(cells, dateStr, estimate, lineIdx+1))
+			note(parser.LineConsumed, "work order")
+			continue
 		}
 		if inDetails {
 			if beginMatch := beginBalanceRe.FindStringSubmatch(line); beginMatch != nil {
-				dateStr := formatDateDash(beginMatch[1])
-				amountStr, _ := normalizeAmount(beginMatch[2])
+				dateStr, err := normalize.Date(beginMatch[1])
+				if err != nil {
+					fail(beginMatch[1], err)
+					continue
+				}
+				amountStr, err := normalize.Amount(beginMatch[2])
+				if err != nil {
+					fail(beginMatch[2], err)
+					continue
+				}
 				txs = append(txs, &parser.Transaction{
 					Date:           dateStr,
 					Directive:      "balance",
 					BalanceAccount: "Assets:Property-Management:CloverLeaf-PM",
 					BalanceAmount:  parser.Amount{Value: amountStr, Currency: "USD"},
+					Line:           lineIdx + 1,
 				})
+				note(parser.LineConsumed, "balance")
 				continue
 			}
-			if !addedEndingBalance {
-				if endMatch := endBalanceRe.FindStringSubmatch(line); endMatch != nil {
-					amountStr, _ := normalizeAmount(endMatch[1])
-					dateStr := statementEndDate
-					if dateStr == "" {
-						dateStr = ""
-					}
-					txs = append(txs, &parser.Transaction{
-						Date:           dateStr,
-						Directive:      "balance",
-						BalanceAccount: "Assets:Property-Management:CloverLeaf-PM",
-						BalanceAmount:  parser.Amount{Value: amountStr, Currency: "USD"},
-					})
-					addedEndingBalance = true
+			if endMatch := endBalanceRe.FindStringSubmatch(line); endMatch != nil {
+				if addedEndingBalance {
+					note(parser.LineIgnored, "repeated ending balance")
 					continue
 				}
+				amountStr, err := normalize.Amount(endMatch[1])
+				if err != nil {
+					fail(endMatch[1], err)
+					continue
+				}
+				txs = append(txs, &parser.Transaction{
+					Date:           statementEndDate,
+					Directive:      "balance",
+					BalanceAccount: "Assets:Property-Management:CloverLeaf-PM",
+					BalanceAmount:  parser.Amount{Value: amountStr, Currency: "USD"},
+					Line:           lineIdx + 1,
+				})
+				addedEndingBalance = true
+				note(parser.LineConsumed, "balance")
+				continue
 			}
 		}
+		isPropertyHeader := true
 		if strings.Contains(line, "2943 Butterfly Palm") {
 			currentProperty = "2943-Butterfly-Palm"
 		} else if strings.Contains(line, "206 Hoover Ave") || strings.Contains(line, "206 Hoover Avenue") {
 			currentProperty = "206-Hoover-Ave"
+		} else {
+			isPropertyHeader = false
 		}
 
 		if !inDetails {
+			note(parser.LineOutside, "")
+			continue
+		}
+		if memoLines[lineIdx] {
+			note(parser.LineConsumed, "memo continuation")
 			continue
 		}
+		if reserveMatch := reserveRe.FindStringSubmatch(line); isPropertyHeader && reserveMatch != nil && statementEndDate != "" {
+			amountStr, err := normalize.Amount(reserveMatch[1])
+			if err != nil {
+				fail(reserveMatch[1], err)
+				continue
+			}
+			txs = append(txs, &parser.Transaction{
+				Date:           statementEndDate,
+				Directive:      "balance",
+				BalanceAccount: parser.ReserveAccount(currentProperty),
+				BalanceAmount:  parser.Amount{Value: amountStr, Currency: "USD"},
+				Line:           lineIdx + 1,
+			})
+		}
 		match := re.FindStringSubmatch(line)
 		if len(match) == 0 {
+			note(classifyDetailLine(line, isPropertyHeader))
 			continue
 		}
 		matches++
 		desc := strings.TrimSpace(match[1])
-		dateStr := match[2]
-		increaseStr, _ := normalizeAmount(match[3])
-		decreaseStr, _ := normalizeAmount(match[4])
-
 		// Convert MM-DD-YYYY to YYYY-MM-DD
-		dateStr = formatDateDash(dateStr)
+		dateStr, err := normalize.Date(match[2])
+		if err != nil {
+			fail(match[2], err)
+			continue
+		}
+		increaseStr, err := normalize.Amount(match[3])
+		if err != nil {
+			fail(match[3], err)
+			continue
+		}
+		decreaseStr, err := normalize.Amount(match[4])
+		if err != nil {
+			fail(match[4], err)
+			continue
+		}
 
 		// Skip if both amounts are 0 or if desc contains certain words
 		if increaseStr == "0.00" && decreaseStr == "0.00" {
+			note(parser.LineIgnored, "zero amount")
 			continue
 		}
+		note(parser.LineConsumed, "transaction")
+
+		memo := desc
+		for next := lineIdx + 1; next < len(lines) && isContinuation(lines[next]); next++ {
+			memo = joinWrapped(memo, strings.TrimSpace(lines[next]))
+			memoLines[next] = true
+		}
 
 		// Determine payee and accounts based on desc (simplified)
 		payee := p.mapPayee(desc)
-		account := p.mapAccount(desc, currentProperty)
+		account, reason := p.mapAccount(desc, currentProperty)
 
 		var postings []parser.Posting
 		if increaseStr != "0.00" {
@@ -130,22 +353,31 @@ func (p *cloverLeafParser) Parse(text string) ([]*parser.Transaction, error) {
 		tx := &parser.Transaction{
 			Date:      dateStr,
 			Payee:     payee,
-			Narration: p.mapNarration(desc),
+			Narration: "Memo: " + memo,
 			Tags:      []string{"#imported"},
 			Links:     p.mapLinks(),
 			Postings:  postings,
+			Line:      lineIdx + 1,
+		}
+		if reason != "" {
+			tx.MarkForReview(reason)
 		}
 		txs = append(txs, tx)
 	}
 
+	txs = parser.DropUnpostedHoldings(txs)
 	slog.Debug("Found potential transaction lines", "count", matches)
 	slog.Info("Completed CloverLeaf parsing", "transactions", len(txs))
-	return txs, nil
+	return txs, coverage, errs.Err()
 }
 
 // mapPayee maps description to payee name
 func (p *cloverLeafParser) mapPayee(desc string) string {
 	switch {
+	case strings.Contains(desc, "Security Deposit"):
+		return "Tenant"
+	case strings.Contains(desc, "Reserve"):
+		return "CloverLeaf Property Management"
 	case strings.Contains(desc, "Rent"):
 		return "Tenant"
 	case strings.Contains(desc, "Management Fee"):
@@ -165,104 +397,113 @@ func (p *cloverLeafParser) mapPayee(desc string) string {
 	}
 }
 
-// mapAccount maps description to account
-func (p *cloverLeafParser) mapAccount(desc, property string) string {
+// mapAccount maps description to account. Lines no rule matches, and
+// deposit and reserve lines outside a property, go to the suspense account
+// with the reason to review them.
+func (p *cloverLeafParser) mapAccount(desc, property string) (account, reason string) {
 	switch {
+	case strings.Contains(desc, "Security Deposit"), strings.Contains(desc, "Reserve"):
+		if property == "" {
+			return parser.SuspenseAccount, parser.NoPropertyReason
+		}
+		if strings.Contains(desc, "Security Deposit") {
+			return parser.DepositAccount(property), ""
+		}
+		return parser.ReserveAccount(property), ""
 	case strings.Contains(desc, "Rent"):
 		if property == "" {
 			property = "2943-Butterfly-Palm"
 		}
-		return fmt.Sprintf("Income:Rent:%s", property)
+		return fmt.Sprintf("Income:Rent:%s", property), ""
 	case strings.Contains(desc, "Management Fee"):
 		if property == "" {
 			property = "2943-Butterfly-Palm"
 		}
-		return fmt.Sprintf("Expenses:Management-Fees:%s", property)
+		return fmt.Sprintf("Expenses:Management-Fees:%s", property), ""
 	case strings.Contains(desc, "Owner Distribution"):
-		return "Equity:Owner-Distributions:Owner-Draw"
+		return "Equity:Owner-Distributions:Owner-Draw", ""
 	case strings.Contains(desc, "Utilities") && strings.Contains(desc, "Electric"):
 		if property == "" {
 			property = "206-Hoover-Ave"
 		}
-		return fmt.Sprintf("Expenses:Utilities:Electric:%s", property)
+		return fmt.Sprintf("Expenses:Utilities:Electric:%s", property), ""
 	case strings.Contains(desc, "Utilities") && strings.Contains(desc, "Water"):
 		if property == "" {
 			property = "206-Hoover-Ave"
 		}
-		return fmt.Sprintf("Expenses:Utilities:Water:%s", property)
+		return fmt.Sprintf("Expenses:Utilities:Water:%s", property), ""
 	case strings.Contains(desc, "Lock Change"):
 		if property == "" {
 			property = "2943-Butterfly-Palm"
 		}
-		return fmt.Sprintf("Expenses:Repairs:%s", property)
+		return fmt.Sprintf("Expenses:Repairs:%s", property), ""
 	case strings.Contains(desc, "General Repairs"):
 		if property == "" {
 			property = "2943-Butterfly-Palm"
 		}
-		return fmt.Sprintf("Expenses:Repairs:%s", property)
+		return fmt.Sprintf("Expenses:Repairs:%s", property), ""
 	case strings.Contains(desc, "EGM Maintenance"):
 		if property == "" {
 			property = "2943-Butterfly-Palm"
 		}
-		return fmt.Sprintf("Expenses:Repairs:%s", property)
+		return fmt.Sprintf("Expenses:Repairs:%s", property), ""
 	default:
-		return "Expenses:Other"
+		return parser.SuspenseAccount, parser.UnmatchedReason
 	}
 }
 
-// mapNarration maps description to narration
-func (p *cloverLeafParser) mapNarration(desc string) string {
+// classifyDetailLine classifies a TRANSACTION DETAILS line that is not a
+// transaction row.
+func classifyDetailLine(line string, isPropertyHeader bool) (parser.LineStatus, string) {
 	switch {
-	case strings.Contains(desc, "Rent"):
-		return "Memo: Rent - Rent (11-2025)"
-	case strings.Contains(desc, "Management Fee") && strings.Contains(desc, "for 11/2025"):
-		return "Memo: Management Fee Expense - Management Fee Expense for 11/2025"
-	case strings.Contains(desc, "Management Fee") && strings.Contains(desc, "Credit for 10/"):
-		return "Memo: Management Fee Expense - Management Fee Expense Credit for 10/"
-	case strings.Contains(desc, "Owner Distribution"):
-		return "Memo: Owner Distribution"
-	case strings.Contains(desc, "Utilities") && strings.Contains(desc, "Electric/Gas Bill - 10/11/25"):
-		return "Memo: Utilities - Electric/Gas Bill - 10/11/25 to 11/12/25"
-	case strings.Contains(desc, "Utilities") && strings.Contains(desc, "Electric"):
-		return "Memo: Utilities - Electric/Gas Bill - 10/6/25 to 10/10/25"
-	case strings.Contains(desc, "Utilities") && strings.Contains(desc, "Water"):
-		return "Memo: Utilities - Water Bill - 10/6/25 to 10/22/25"
-	case strings.Contains(desc, "Lock Change"):
-		return "Memo: Lock Change - Lock change - Landlord compliance"
-	case strings.Contains(desc, "General Repairs"):
-		return "Memo: General Repairs - Trash out"
-	case strings.Contains(desc, "EGM Maintenance"):
-		return "Memo: EGM Maintenance - Measured current fridge 65in Heigh, 28in width,"
+	case strings.TrimSpace(line) == "":
+		return parser.LineIgnored, "blank"
+	case isPropertyHeader:
+		return parser.LineConsumed, "property header"
+	case strings.Contains(line, "Description") && strings.Contains(line, "Increase"):
+		return parser.LineIgnored, "column header"
+	case subtotalRe.MatchString(line):
+		return parser.LineIgnored, "subtotal"
+	case pageFooterRe.MatchString(line):
+		return parser.LineIgnored, "page footer"
+	case !parser.HasAmount(line):
+		// Payee group headers and descriptions wrapped onto another line.
+		return parser.LineIgnored, "text without amounts"
 	default:
-		return ""
+		return parser.LineUnrecognized, ""
 	}
 }
 
-// mapLinks returns the standard links
-func (p *cloverLeafParser) mapLinks() map[string]string {
-	return map[string]string{
-		"comments": "",
+// isContinuation reports whether line continues the description of the
+// transaction row above it: wrapped text with no amounts that is not a
+// header, subtotal or footer.
+func isContinuation(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "", parser.HasAmount(line):
+		return false
+	case strings.Contains(line, "2943 Butterfly Palm"), strings.Contains(line, "206 Hoover Ave"):
+		return false
+	case strings.Contains(line, detailsSection), strings.Contains(line, workOrdersSection):
+		return false
+	case subtotalRe.MatchString(line), pageFooterRe.MatchString(line):
+		return false
 	}
+	return true
 }
 
-func normalizeAmount(amount string) (string, bool) {
-	trimmed := strings.TrimSpace(amount)
-	trimmed = strings.TrimPrefix(trimmed, "$")
-	isNegative := strings.HasPrefix(trimmed, "(") && strings.HasSuffix(trimmed, ")")
-	trimmed = strings.TrimPrefix(trimmed, "(")
-	trimmed = strings.TrimSuffix(trimmed, ")")
-	trimmed = strings.ReplaceAll(trimmed, ",", "")
-	trimmed = strings.ReplaceAll(trimmed, " ", "")
-	if isNegative {
-		return fmt.Sprintf("-%s", trimmed), true
+// joinWrapped appends a wrapped continuation to text, without a space when
+// the line broke inside a date or hyphenated word, like "10/" + "2025".
+func joinWrapped(text, continuation string) string {
+	if strings.HasSuffix(text, "/") || (strings.HasSuffix(text, "-") && !strings.HasSuffix(text, " -")) {
+		return text + continuation
 	}
-	return trimmed, false
+	return text + " " + continuation
 }
 
-func formatDateDash(dateStr string) string {
-	parts := strings.Split(strings.TrimSpace(dateStr), "-")
-	if len(parts) != 3 {
-		return strings.TrimSpace(dateStr)
+// mapLinks returns the standard links
+func (p *cloverLeafParser) mapLinks() map[string]string {
+	return map[string]string{
+		"comments": "",
 	}
-	return fmt.Sprintf("%s-%s-%s", parts[2], parts[0], parts[1])
 }
@@ -2,14 +2,17 @@
 package cloverleaf_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/jason-riddle/ledger-go/internal/cloverleaf"
+	"github.com/jason-riddle/ledger-go/internal/parser"
 	"github.com/jason-riddle/ledger-go/internal/shared"
 )
 
@@ -27,49 +30,188 @@ func TestGoldenFiles(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	txs = shared.SortTransactions(txs, shared.OrderDate)
 
-	// Format to .bean
-	var lines []string
-	accountWidth, amountWidth := shared.ComputePostingWidths(txs)
-	for _, tx := range txs {
-		if tx.Directive == "balance" {
-			lines = append(lines, shared.FormatBalanceLine(tx, accountWidth, amountWidth))
-			lines = append(lines, "")
-			continue
-		}
-		line := fmt.Sprintf("%s * \"%s\"", tx.Date, tx.Payee)
-		if tx.Narration != "" {
-			line += fmt.Sprintf(" \"%s\"", tx.Narration)
-		}
-		if len(tx.Tags) > 0 {
-			line += " " + strings.Join(tx.Tags, " ")
-		}
-		lines = append(lines, line)
-		// Sort link keys for consistent output
-		var keys []string
-		for key := range tx.Links {
-			keys = append(keys, key)
-		}
-		sort.Strings(keys)
-		for _, key := range keys {
-			lines = append(lines, fmt.Sprintf("  %s: \"%s\"", key, tx.Links[key]))
+	for _, format := range []shared.OutputFormat{shared.OutputBeancount, shared.OutputLedger, shared.OutputHledger} {
+		t.Run(format.String(), func(t *testing.T) {
+			// Format in date order
+			var output strings.Builder
+			if err := shared.WriteEntries(&output, txs, format); err != nil {
+				t.Fatal(err)
+			}
+
+			// Load golden
+			goldenPath := filepath.Join("..", "..", "tests", "golden", "cloverleaf", "cloverleaf_2025-12-11_statement"+format.Extension())
+			golden, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Compare
+			if strings.TrimSpace(output.String()) != strings.TrimSpace(string(golden)) {
+				t.Errorf("Output does not match golden file.\nGot:\n%s\n\nWant:\n%s", output.String(), string(golden))
+			}
+		})
+	}
+}
+
+func TestParseCoverage(t *testing.T) {
+	fixturePath := filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt")
+	fixture, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A charge row with a non-date layout the transaction rule cannot match.
+	newCharge := "  HOA Dues - Quarterly assessment                               Q4 2025             $0.00          $210.00"
+	modified := strings.Replace(string(fixture), "\n\n  Net $ (785.70)", "\n"+newCharge+"\n  Net $ (785.70)", 1)
+
+	tests := []struct {
+		name      string
+		text      string
+		wantLines []int
+	}{
+		{name: "fixture", text: string(fixture)},
+		{name: "unknown row", text: modified, wantLines: []int{167}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, coverage, err := cloverleaf.NewParser().ParseCoverage(context.Background(), tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, line := range coverage.Unrecognized() {
+				got = append(got, line.Line)
+			}
+			if !reflect.DeepEqual(got, tt.wantLines) {
+				t.Errorf("unrecognized lines = %v, want %v", got, tt.wantLines)
+			}
+			if tt.wantLines != nil && !strings.Contains(fmt.Sprint(coverage.Err()), "line 167: HOA Dues") {
+				t.Errorf("Err() = %v, want line 167 listed", coverage.Err())
+			}
+
+			rules := map[int]string{}
+			for _, line := range coverage.Lines {
+				rules[line.Line] = line.Status.String() + " " + line.Rule
+			}
+			want := map[int]string{
+				64:  "outside ",
+				127: "consumed balance",
+				130: "ignored text without amounts",
+				137: "consumed property header",
+				139: "consumed transaction",
+				143: "ignored subtotal",
+				149: "consumed memo continuation",
+			}
+			for line, rule := range want {
+				if rules[line] != rule {
+					t.Errorf("line %d = %q, want %q", line, rules[line], rule)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Replace(string(fixture), "11-05-2025", "11-35-2025", 1)
+	text = strings.Replace(text, "11-19-2025", "19-11-2025", 1)
+
+	txs, err := cloverleaf.NewParser().Parse(text)
+	var parseErrs parser.ParseErrors
+	if !errors.As(err, &parseErrs) {
+		t.Fatalf("Parse() error = %v, want parser.ParseErrors", err)
+	}
+	if !errors.Is(err, parser.ErrInvalidDate) {
+		t.Errorf("Parse() error = %v, want ErrInvalidDate", err)
+	}
+	tests := []struct {
+		line   int
+		column int
+		field  string
+	}{
+		{line: 156, column: 81, field: "11-35-2025"},
+		{line: 166, column: 81, field: "19-11-2025"},
+	}
+	if len(parseErrs) != len(tests) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(parseErrs), len(tests), err)
+	}
+	for i, tt := range tests {
+		got := parseErrs[i]
+		if got.Institution != "cloverleaf" || got.Line != tt.line || got.Column != tt.column || !strings.Contains(got.Err.Error(), tt.field) {
+			t.Errorf("error %d = %+v, want line %d column %d for %q", i, got, tt.line, tt.column, tt.field)
 		}
-		for _, p := range tx.Postings {
-			lines = append(lines, shared.FormatPostingLine(p, accountWidth, amountWidth))
+		if got.Text[got.Column-1:got.Column-1+len(tt.field)] != tt.field {
+			t.Errorf("column %d does not point at %q in %q", got.Column, tt.field, got.Text)
 		}
-		lines = append(lines, "")
 	}
-	output := strings.Join(lines, "\n")
+	// The other rows still parse.
+	if len(txs) != 12 {
+		t.Errorf("Parse() returned %d entries alongside the errors, want 12", len(txs))
+	}
+}
 
-	// Load golden
-	goldenPath := filepath.Join("..", "..", "tests", "golden", "cloverleaf", "cloverleaf_2025-12-11_statement.bean")
-	golden, err := os.ReadFile(goldenPath)
+func TestParseMemo(t *testing.T) {
+	text := strings.Join([]string{
+		"TRANSACTION DETAILS",
+		" 206 Hoover Ave, San Antonio, TX 78225 ( Reserve: $450.00 )",
+		"  Rent - Rent (12-2025)                                      12-01-2025   $1,600.00       $0.00   $1,600.00",
+		"  HVAC Repair - Replaced blower motor; tenant reported no    12-09-2025       $0.00     $420.00   $1,180.00",
+		"  heat in the back bed-",
+		"  room. Invoice 5521 paid 12/",
+		"  10.",
+		"",
+		"  Management Fee Expense - Management Fee Expense for 12/    12-03-2025       $0.00     $144.00   $1,036.00",
+		"  2025",
+		"  Net $1,036.00                                                             $1,600.00     $564.00",
+	}, "\n")
+
+	txs, err := cloverleaf.NewParser().Parse(text)
 	if err != nil {
 		t.Fatal(err)
 	}
+	want := []string{
+		"Memo: Rent - Rent (12-2025)",
+		"Memo: HVAC Repair - Replaced blower motor; tenant reported no heat in the back bed-room. Invoice 5521 paid 12/10.",
+		"Memo: Management Fee Expense - Management Fee Expense for 12/2025",
+	}
+	var got []string
+	for _, tx := range txs {
+		got = append(got, tx.Narration)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("narrations =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestParseUnmatchedDescription(t *testing.T) {
+	text := strings.Join([]string{
+		"TRANSACTION DETAILS",
+		" 206 Hoover Ave, San Antonio, TX 78225 ( Reserve: $450.00 )",
+		"  Rent - Rent (12-2025)                                      12-01-2025   $1,600.00       $0.00   $1,600.00",
+		"  Pest Control - Quarterly                                   12-09-2025       $0.00      $95.00   $1,505.00",
+		"  Net $1,505.00                                                             $1,600.00      $95.00",
+	}, "\n")
 
-	// Compare
-	if strings.TrimSpace(output) != strings.TrimSpace(string(golden)) {
-		t.Errorf("Output does not match golden file.\nGot:\n%s\n\nWant:\n%s", output, string(golden))
+	txs, err := cloverleaf.NewParser().Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("Parse() returned %d entries, want 2", len(txs))
+	}
+	if txs[0].Flag != "" || txs[0].Links[parser.ReviewKey] != "" {
+		t.Errorf("rent flag = %q, review = %q; want neither", txs[0].Flag, txs[0].Links[parser.ReviewKey])
+	}
+	unmatched := txs[1]
+	if unmatched.Flag != parser.FlagReview || unmatched.Links[parser.ReviewKey] != "no account rule matches the description" {
+		t.Errorf("unmatched flag = %q, review = %q; want flagged with a reason", unmatched.Flag, unmatched.Links[parser.ReviewKey])
+	}
+	if got := unmatched.Postings[1].Account; got != parser.SuspenseAccount {
+		t.Errorf("unmatched account = %q, want %q", got, parser.SuspenseAccount)
 	}
 }
@@ -0,0 +1,72 @@
+// internal/cloverleaf/summary.go
+package cloverleaf
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/normalize"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// summarySection is the statement's first page of totals. It prints two
+// columns of label and amount pairs side by side.
+const summarySection = "SUMMARY"
+
+// summaryItemRe matches one label and amount pair, like "Total Income (+)
+// $1,600.00" or "Total Distribution: $1,053.10".
+var summaryItemRe = regexp.MustCompile(`([A-Za-z][A-Za-z ]*?(?:\s*\([+-]\))?):?\s+(\$\s*\(?[\d,]+\.\d{2}\)?)`)
+
+// summaryAccounts maps summary labels to the accounts they total and
+// whether the statement prints the total with the opposite sign to the
+// postings. Labels not listed, like reserves and unpaid bills, have no
+// entries to compare.
+var summaryAccounts = map[string]struct {
+	account string
+	negate  bool
+}{
+	"Total Income (+)":   {account: "Income", negate: true},
+	"Total Expenses (-)": {account: "Expenses"},
+	"Total Distribution": {account: "Equity:Owner-Distributions"},
+}
+
+// ParseSummary reads the totals in the SUMMARY section, from its heading to
+// the page footer.
+func (p *cloverLeafParser) ParseSummary(ctx context.Context, text string) ([]parser.SummaryTotal, error) {
+	var totals []parser.SummaryTotal
+	var errs parser.ParseErrors
+	inSummary := false
+	for lineIdx, line := range strings.Split(text, "\n") {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == summarySection {
+			inSummary = true
+			continue
+		}
+		if !inSummary {
+			continue
+		}
+		if pageFooterRe.MatchString(line) {
+			break
+		}
+		for _, match := range summaryItemRe.FindAllStringSubmatch(line, -1) {
+			label := strings.TrimSpace(match[1])
+			amount, err := normalize.Amount(match[2])
+			if err != nil {
+				errs = append(errs, parser.NewParseError(institution, lineIdx+1, line, match[2], err))
+				continue
+			}
+			total := parser.SummaryTotal{Label: label, Amount: amount, Line: lineIdx + 1}
+			if mapping, ok := summaryAccounts[label]; ok {
+				total.Account = mapping.account
+				if mapping.negate {
+					total.Amount = normalize.Negate(amount)
+				}
+			}
+			totals = append(totals, total)
+		}
+	}
+	return totals, errs.Err()
+}
@@ -0,0 +1,106 @@
+// internal/cloverleaf/summary_test.go
+package cloverleaf_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/cloverleaf"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+	"github.com/jason-riddle/ledger-go/internal/shared"
+)
+
+func TestParseSummary(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		// replace edits the fixture before parsing.
+		replace      [2]string
+		wantMismatch []string
+	}{
+		{name: "fixture"},
+		{
+			name:         "income differs",
+			replace:      [2]string{"Total Income (+)                                 $1,600.00", "Total Income (+)                                 $1,650.00"},
+			wantMismatch: []string{"line 49: Total Income (+) (Income, all properties): statement -1650.00, entries -1600.00"},
+		},
+		{
+			name:         "distribution differs",
+			replace:      [2]string{"Total Distribution: $1,053.10", "Total Distribution: $1,035.10"},
+			wantMismatch: []string{"line 59: Total Distribution (Equity:Owner-Distributions, all properties): statement 1035.10, entries 1053.10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := string(fixture)
+			if tt.replace[0] != "" {
+				if !strings.Contains(text, tt.replace[0]) {
+					t.Fatalf("fixture has no %q", tt.replace[0])
+				}
+				text = strings.Replace(text, tt.replace[0], tt.replace[1], 1)
+			}
+			p := cloverleaf.NewParser()
+			txs, err := p.Parse(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			totals, err := p.(parser.Summarizer).ParseSummary(context.Background(), text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = shared.CheckSummary(txs, totals)
+			if tt.wantMismatch == nil {
+				if err != nil {
+					t.Errorf("CheckSummary() error = %v, want nil", err)
+				}
+				return
+			}
+			var mismatch *shared.SummaryMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("CheckSummary() error = %v, want *SummaryMismatchError", err)
+			}
+			for _, want := range tt.wantMismatch {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("CheckSummary() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSummaryTotals(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	totals, err := cloverleaf.NewParser().(parser.Summarizer).ParseSummary(context.Background(), string(fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checked := map[string]parser.SummaryTotal{}
+	for _, total := range totals {
+		if total.Account != "" {
+			checked[total.Label] = total
+		}
+	}
+	want := map[string]parser.SummaryTotal{
+		"Total Income (+)":   {Label: "Total Income (+)", Account: "Income", Amount: "-1600.00", Line: 49},
+		"Total Expenses (-)": {Label: "Total Expenses (-)", Account: "Expenses", Amount: "902.70", Line: 51},
+		"Total Distribution": {Label: "Total Distribution", Account: "Equity:Owner-Distributions", Amount: "1053.10", Line: 59},
+	}
+	if !reflect.DeepEqual(checked, want) {
+		t.Errorf("checked totals = %+v, want %+v", checked, want)
+	}
+}
@@ -0,0 +1,79 @@
+// internal/cloverleaf/units.go
+package cloverleaf
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/layout"
+	"github.com/jason-riddle/ledger-go/internal/normalize"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// unitsSection lists each managed unit with its lease and the security
+// deposit the property manager holds for it.
+const unitsSection = "MANAGED UNITS"
+
+// unitHeadings are the managed units table headings, left to right.
+var unitHeadings = []string{"Unit", "Lease Info", "Move in Date", "Current Rent", "Deposit Held", "Balance"}
+
+var (
+	// unitRe matches a unit that starts with its street number.
+	unitRe = regexp.MustCompile(`^\d+\s+\S`)
+	// reserveRe matches the reserve printed after a property's address,
+	// like "( Reserve: $450.00 )".
+	reserveRe = regexp.MustCompile(`\(\s*Reserve:\s*(\$?\s*[\d,]+\.\d{2})\s*\)`)
+)
+
+// unitColumns returns the columns of a managed units table header, or nil
+// when line is not one.
+func unitColumns(line layout.Line) []layout.Column {
+	columns := layout.FindColumns(line, unitHeadings...)
+	for _, name := range []string{"Unit", "Deposit Held"} {
+		if !slices.ContainsFunc(columns, func(c layout.Column) bool { return c.Name == name }) {
+			return nil
+		}
+	}
+	layout.AlignLeft(columns, "Unit", "Lease Info")
+	layout.AlignRight(columns, estimateWidth, "Current Rent", "Deposit Held", "Balance")
+	return columns
+}
+
+// isUnitRow reports whether cells are a unit with its deposit held.
+func isUnitRow(cells map[string]string) bool {
+	return unitRe.MatchString(cells["Unit"]) && cells["Deposit Held"] != ""
+}
+
+// classifyUnitLine classifies a MANAGED UNITS line that is not a unit row.
+func classifyUnitLine(line string) (parser.LineStatus, string) {
+	switch {
+	case strings.TrimSpace(line) == "":
+		return parser.LineIgnored, "blank"
+	case strings.HasPrefix(strings.TrimSpace(line), "Totals"):
+		return parser.LineIgnored, "subtotal"
+	case pageFooterRe.MatchString(line):
+		return parser.LineIgnored, "page footer"
+	case !parser.HasAmount(line):
+		// City lines and lease terms printed below the unit.
+		return parser.LineIgnored, "text without amounts"
+	default:
+		return parser.LineUnrecognized, ""
+	}
+}
+
+// depositBalance asserts the deposit held for a unit row on date. Deposits
+// are owed to tenants, so the liability balance is negative.
+func depositBalance(cells map[string]string, date string, line int) (*parser.Transaction, error) {
+	amount, err := normalize.Amount(cells["Deposit Held"])
+	if err != nil {
+		return nil, err
+	}
+	return &parser.Transaction{
+		Date:           date,
+		Directive:      "balance",
+		BalanceAccount: parser.DepositAccount(normalize.PropertySlug(cells["Unit"])),
+		BalanceAmount:  parser.Amount{Value: normalize.Negate(amount), Currency: "USD"},
+		Line:           line,
+	}, nil
+}
@@ -0,0 +1,77 @@
+// internal/cloverleaf/units_test.go
+package cloverleaf_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/cloverleaf"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestParseDepositsAndReserves(t *testing.T) {
+	text := "" +
+		"                                                      12-01-2025 to 12-31-2025     01-09-2026\n" +
+		"TRANSACTION DETAILS\n" +
+		"  Security Deposit - Deposit received from tenant            12-01-2025     $900.00       $0.00     $900.00\n" +
+		" 206 Hoover Ave, San Antonio, TX 78225 ( Reserve: $600.00 )\n" +
+		"  Security Deposit - Deposit received from tenant            12-02-2025   $1,500.00       $0.00   $1,500.00\n" +
+		"  Reserve Contribution - Reserve top-up                      12-03-2025       $0.00     $150.00   $1,350.00\n" +
+		"  Net $1,350.00                                                             $1,500.00     $150.00\n" +
+		"\n" +
+		" 2943 Butterfly Palm, San Antonio, TX 78245 ( Reserve: $450.00 )\n" +
+		"  Security Deposit Refund - Deposit returned to tenant       12-05-2025       $0.00   $2,000.00    $(650.00)\n" +
+		"  Net $ (2,000.00)                                                              $0.00   $2,000.00\n" +
+		"\n" +
+		"MANAGED UNITS\n" +
+		"\n" +
+		" Unit                    Lease Info                                    Move in Date   Current Rent    Deposit Held    Balance\n" +
+		"\n" +
+		" 2943 Butterfly Palm     Vacant                                                              $0.00           $0.00\n" +
+		" San Antonio, TX 78245\n" +
+		"\n" +
+		" 206 Hoover Avenue       George Mahara                                 12-01-2025         $1,500.00       $1,500.00\n" +
+		" San Antonio, TX 78225   12-01-2025 to 11-30-2026\n" +
+		"\n" +
+		" Totals                                                                                  $1,500.00       $1,500.00      $0.00\n"
+
+	txs, coverage, err := cloverleaf.NewParser().ParseCoverage(context.Background(), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := coverage.Unrecognized(); len(got) != 0 {
+		t.Errorf("unrecognized lines = %v, want none", got)
+	}
+
+	var got []string
+	for _, tx := range txs {
+		if tx.Directive == "balance" {
+			got = append(got, fmt.Sprintf("%d: %s balance %s %s", tx.Line, tx.Date, tx.BalanceAccount, tx.BalanceAmount.Value))
+			continue
+		}
+		entry := fmt.Sprintf("%d: %s %s", tx.Line, tx.Date, tx.Payee)
+		if tx.Flag != "" {
+			entry = fmt.Sprintf("%d: %s %s %s (%s)", tx.Line, tx.Date, tx.Flag, tx.Payee, tx.Links[parser.ReviewKey])
+		}
+		for _, p := range tx.Postings {
+			entry += fmt.Sprintf(", %s %s", p.Account, p.Amount.Value)
+		}
+		got = append(got, entry)
+	}
+	// The 2943 Butterfly Palm reserve is never posted to, so its balance is
+	// not asserted.
+	want := []string{
+		"3: 2025-12-01 ! Tenant (no property for the line), Expenses:Unidentified -900.00, Assets:Property-Management:CloverLeaf-PM 900.00",
+		"4: 2025-12-31 balance Assets:Property-Reserves:206-Hoover-Ave 600.00",
+		"5: 2025-12-02 Tenant, Liabilities:Security-Deposits-Owed:206-Hoover-Ave -1500.00, Assets:Property-Management:CloverLeaf-PM 1500.00",
+		"6: 2025-12-03 CloverLeaf Property Management, Assets:Property-Management:CloverLeaf-PM -150.00, Assets:Property-Reserves:206-Hoover-Ave 150.00",
+		"10: 2025-12-05 Tenant, Assets:Property-Management:CloverLeaf-PM -2000.00, Liabilities:Security-Deposits-Owed:2943-Butterfly-Palm 2000.00",
+		"17: 2025-12-31 balance Liabilities:Security-Deposits-Owed:2943-Butterfly-Palm 0.00",
+		"20: 2025-12-31 balance Liabilities:Security-Deposits-Owed:206-Hoover-Ave -1500.00",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("entries =\n%q\nwant\n%q", got, want)
+	}
+}
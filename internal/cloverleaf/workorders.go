@@ -0,0 +1,118 @@
+// internal/cloverleaf/workorders.go
+package cloverleaf
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/layout"
+	"github.com/jason-riddle/ledger-go/internal/normalize"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// workOrdersSection lists maintenance the property manager has not closed.
+// Each work order becomes a note on the property's repairs account.
+const workOrdersSection = "OPEN WORK ORDERS"
+
+// workOrderHeadings are the work order table headings the parser reads,
+// left to right. Vendor and Status are printed only on some statements.
+var workOrderHeadings = []string{"Work Order #", "Date", "Location", "Vendor", "Description", "Status", "Estimate"}
+
+var (
+	workOrderNumberRe = regexp.MustCompile(`^\d+$`)
+	workOrderTotalRe  = regexp.MustCompile(`^\s*Total\b`)
+	// sectionHeadingRe matches an all-caps heading such as MANAGED UNITS,
+	// which ends the work orders.
+	sectionHeadingRe = regexp.MustCompile(`^\s*[A-Z][A-Z &]*[A-Z]\s*$`)
+)
+
+// estimateWidth is the widest estimate the right-aligned Estimate column
+// holds, like $10,000.00 with a space either side.
+const estimateWidth = 12 * layout.TextCellWidth
+
+// workOrderColumns returns the columns of a work order table header, or nil
+// when line is not one.
+func workOrderColumns(line layout.Line) []layout.Column {
+	columns := layout.FindColumns(line, workOrderHeadings...)
+	for _, name := range []string{"Work Order #", "Date", "Location", "Description"} {
+		if !slices.ContainsFunc(columns, func(c layout.Column) bool { return c.Name == name }) {
+			return nil
+		}
+	}
+	layout.AlignLeft(columns, "Location", "Vendor", "Description", "Status")
+	layout.AlignRight(columns, estimateWidth, "Estimate")
+	return columns
+}
+
+// isWorkOrderRow reports whether cells start a work order.
+func isWorkOrderRow(cells map[string]string) bool {
+	return workOrderNumberRe.MatchString(cells["Work Order #"])
+}
+
+// isWorkOrderText reports whether a non-blank line continues the work order
+// above it, like its description printed below the row.
+func isWorkOrderText(line string, cells map[string]string) bool {
+	switch {
+	case isWorkOrderRow(cells), strings.Contains(line, "\f"), parser.HasAmount(line):
+		return false
+	case workOrderTotalRe.MatchString(line), pageFooterRe.MatchString(line), sectionHeadingRe.MatchString(line):
+		return false
+	}
+	return true
+}
+
+// classifyWorkOrderLine classifies an OPEN WORK ORDERS line that is not a
+// work order or its text.
+func classifyWorkOrderLine(line string) (parser.LineStatus, string) {
+	switch {
+	case strings.TrimSpace(line) == "":
+		return parser.LineIgnored, "blank"
+	case workOrderTotalRe.MatchString(line):
+		return parser.LineIgnored, "subtotal"
+	case pageFooterRe.MatchString(line):
+		return parser.LineIgnored, "page footer"
+	case !parser.HasAmount(line):
+		return parser.LineIgnored, "text without amounts"
+	default:
+		return parser.LineUnrecognized, ""
+	}
+}
+
+// workOrderNote builds the note for a work order from its cells, with the
+// date and estimate already normalized. The note is attached to the repairs
+// account of the property named at the start of the location, which must
+// not be blank.
+func workOrderNote(cells map[string]string, date, estimate string, line int) *parser.Transaction {
+	number := cells["Work Order #"]
+	location := cells["Location"]
+	street, _, _ := strings.Cut(location, ",")
+	account := "Expenses:Repairs:" + normalize.PropertySlug(street)
+
+	comment := "Open work order " + number
+	if description := cells["Description"]; description != "" {
+		comment += ": " + description
+	}
+	metadata := map[string]string{"work-order": number}
+	if location != "" {
+		metadata["location"] = location
+	}
+	if estimate != "" {
+		metadata["estimate"] = fmt.Sprintf("%s USD", estimate)
+	}
+	if vendor := cells["Vendor"]; vendor != "" {
+		metadata["vendor"] = vendor
+	}
+	if status := cells["Status"]; status != "" {
+		metadata["status"] = status
+	}
+	return &parser.Transaction{
+		Date:        date,
+		Directive:   "note",
+		NoteAccount: account,
+		Narration:   comment,
+		Links:       metadata,
+		Line:        line,
+	}
+}
@@ -0,0 +1,109 @@
+// internal/cloverleaf/workorders_test.go
+package cloverleaf_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/cloverleaf"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestParseWorkOrders(t *testing.T) {
+	text := "" +
+		"OPEN WORK ORDERS\n" +
+		"\n" +
+		" Work Order #   Date         Location                          Vendor            Description                  Status        Estimate\n" +
+		"\n" +
+		" 120001         11-20-2025   2943 Butterfly Palm, San Antonio  Ace Plumbing      Kitchen sink leaking         Scheduled      $350.00\n" +
+		"                                                                                 under the cabinet\n" +
+		" 120002         11-21-2025   206 Hoover Avenue, San Antonio    Cool Air HVAC     AC not cooling               New          $1,200.00\n" +
+		" 120003         11-22-2025                                     Handy Pro         Gate latch broken            New             $75.00\n" +
+		"\n" +
+		" Total Estimated Amount                                                                                                  $1,625.00\n" +
+		"\n" +
+		"MANAGED UNITS\n"
+
+	txs, coverage, err := cloverleaf.NewParser().ParseCoverage(context.Background(), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := coverage.Unrecognized(); len(got) != 0 {
+		t.Errorf("unrecognized lines = %v, want none", got)
+	}
+	// The work order without a location is skipped.
+	if got := coverage.Lines[7]; got.Status != parser.LineIgnored || got.Rule != "work order without a location" {
+		t.Errorf("line 8 = %v %q, want ignored work order without a location", got.Status, got.Rule)
+	}
+
+	want := []*parser.Transaction{
+		{
+			Date:        "2025-11-20",
+			Directive:   "note",
+			NoteAccount: "Expenses:Repairs:2943-Butterfly-Palm",
+			Narration:   "Open work order 120001: Kitchen sink leaking under the cabinet",
+			Links: map[string]string{
+				"work-order": "120001",
+				"location":   "2943 Butterfly Palm, San Antonio",
+				"vendor":     "Ace Plumbing",
+				"status":     "Scheduled",
+				"estimate":   "350.00 USD",
+			},
+			Line: 5,
+		},
+		{
+			Date:        "2025-11-21",
+			Directive:   "note",
+			NoteAccount: "Expenses:Repairs:206-Hoover-Ave",
+			Narration:   "Open work order 120002: AC not cooling",
+			Links: map[string]string{
+				"work-order": "120002",
+				"location":   "206 Hoover Avenue, San Antonio",
+				"vendor":     "Cool Air HVAC",
+				"status":     "New",
+				"estimate":   "1200.00 USD",
+			},
+			Line: 7,
+		},
+	}
+	if !reflect.DeepEqual(txs, want) {
+		for _, tx := range txs {
+			t.Logf("got %+v", *tx)
+		}
+		t.Errorf("ParseCoverage() notes differ from want")
+	}
+}
+
+func TestWorkOrdersCoverage(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, coverage, err := cloverleaf.NewParser().ParseCoverage(context.Background(), string(fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := map[int]string{}
+	for _, line := range coverage.Lines {
+		rules[line.Line] = line.Section + ": " + line.Status.String() + " " + line.Rule
+	}
+	want := map[int]string{
+		179: "OPEN WORK ORDERS: ignored section heading",
+		182: "OPEN WORK ORDERS: ignored column header",
+		184: "OPEN WORK ORDERS: consumed work order",
+		185: "OPEN WORK ORDERS: consumed work order text",
+		187: "OPEN WORK ORDERS: consumed work order text",
+		190: "OPEN WORK ORDERS: ignored subtotal",
+		195: "OPEN WORK ORDERS: ignored page footer",
+		196: "MANAGED UNITS: ignored section heading",
+	}
+	for line, rule := range want {
+		if rules[line] != rule {
+			t.Errorf("line %d = %q, want %q", line, rules[line], rule)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// internal/export/csv.go
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSVHeader lists the columns written by WriteCSV.
+var CSVHeader = []string{
+	"schema_version", "index", "line", "kind", "date", "payee", "narration",
+	"tags", "metadata", "account", "amount", "currency",
+}
+
+// WriteCSV writes one row per posting, balance and note, in statement order.
+func WriteCSV(w io.Writer, doc *Document) error {
+	type row struct {
+		index  int
+		fields []string
+	}
+	version := strconv.Itoa(doc.SchemaVersion)
+	var rows []row
+	for _, tx := range doc.Transactions {
+		tags := strings.Join(tx.Tags, " ")
+		metadata := formatMetadata(tx.Metadata)
+		for _, p := range tx.Postings {
+			rows = append(rows, row{tx.Index, []string{
+				version, strconv.Itoa(tx.Index), strconv.Itoa(tx.Line), "transaction", tx.Date,
+				tx.Payee, tx.Narration, tags, metadata, p.Account, p.Amount, p.Currency,
+			}})
+		}
+	}
+	for _, b := range doc.Balances {
+		rows = append(rows, row{b.Index, []string{
+			version, strconv.Itoa(b.Index), strconv.Itoa(b.Line), "balance", b.Date,
+			"", "", "", "", b.Account, b.Amount, b.Currency,
+		}})
+	}
+	for _, n := range doc.Notes {
+		rows = append(rows, row{n.Index, []string{
+			version, strconv.Itoa(n.Index), strconv.Itoa(n.Line), "note", n.Date,
+			"", n.Comment, "", formatMetadata(n.Metadata), n.Account, "", "",
+		}})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].index < rows[j].index })
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(r.fields); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatMetadata renders metadata as sorted key=value pairs joined by ";".
+func formatMetadata(metadata map[string]string) string {
+	var keys []string
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + metadata[k]
+	}
+	return strings.Join(pairs, ";")
+}
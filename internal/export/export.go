@@ -0,0 +1,263 @@
+// internal/export/export.go
+
+// Package export serializes parsed statements to machine-readable formats
+// and reads them back.
+//
+// # JSON schema (version 1)
+//
+//	{
+//	  "schema_version": 1,
+//	  "statement": {
+//	    "institution": "cloverleaf",      // parser that produced the entries
+//	    "source": "statement.pdf",        // input file as given on the command line
+//	    "source_sha256": "…",             // hex digest of the input file, if known
+//	    "start_date": "2025-11-01",       // earliest transaction or balance date
+//	    "end_date": "2025-11-30"          // latest transaction or balance date
+//	  },
+//	  "transactions": [{
+//	    "index": 1,                       // position in statement order
+//	    "line": 132,                      // 1-based line in the extracted text, 0 if unknown
+//	    "date": "2025-11-06",
+//	    "flag": "!",                      // present only for entries needing review
+//	    "payee": "Jason Riddle",
+//	    "narration": "Memo: Owner Distribution",
+//	    "tags": ["#imported"],
+//	    "metadata": {"comments": ""},
+//	    "postings": [{"account": "Equity:Owner-Distributions:Owner-Draw", "amount": "1053.10", "currency": "USD"}]
+//	  }],
+//	  "balances": [{
+//	    "index": 0, "line": 127, "date": "2025-11-01",
+//	    "account": "Assets:Property-Management:CloverLeaf-PM", "amount": "714.29", "currency": "USD"
+//	  }],
+//	  "notes": [{                         // omitted when there are none
+//	    "index": 30, "line": 185, "date": "2025-10-13",
+//	    "account": "Expenses:Repairs:206-Hoover-Ave", "comment": "Open work order 110552: …",
+//	    "metadata": {"work-order": "110552"}
+//	  }]
+//	}
+//
+// Amounts are decimal strings exactly as the parser produced them. Indexes
+// are shared between transactions, balances and notes so statement order
+// survives a round trip.
+//
+// # CSV layout (version 1)
+//
+// One row per posting, balance and note, with the header
+//
+//	schema_version,index,line,kind,date,payee,narration,tags,metadata,account,amount,currency
+//
+// where kind is "transaction", "balance" or "note", tags are space separated
+// and metadata is rendered as key=value pairs joined by ";". A note's comment
+// is written as its narration.
+package export
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// SchemaVersion is the version of the JSON and CSV layouts written by this package.
+const SchemaVersion = 1
+
+// Document is the top-level JSON export.
+type Document struct {
+	SchemaVersion int           `json:"schema_version"`
+	Statement     Statement     `json:"statement"`
+	Transactions  []Transaction `json:"transactions"`
+	Balances      []Balance     `json:"balances"`
+	Notes         []Note        `json:"notes,omitempty"`
+}
+
+// Statement describes where the entries came from.
+type Statement struct {
+	Institution  string `json:"institution"`
+	Source       string `json:"source"`
+	SourceSHA256 string `json:"source_sha256,omitempty"`
+	StartDate    string `json:"start_date,omitempty"`
+	EndDate      string `json:"end_date,omitempty"`
+}
+
+// Transaction is a serialized non-balance entry.
+type Transaction struct {
+	Index     int               `json:"index"`
+	Line      int               `json:"line"`
+	Date      string            `json:"date"`
+	Flag      string            `json:"flag,omitempty"`
+	Payee     string            `json:"payee"`
+	Narration string            `json:"narration"`
+	Tags      []string          `json:"tags"`
+	Metadata  map[string]string `json:"metadata"`
+	Postings  []Posting         `json:"postings"`
+}
+
+// Posting is a serialized posting.
+type Posting struct {
+	Account  string `json:"account"`
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Balance is a serialized balance assertion.
+type Balance struct {
+	Index    int    `json:"index"`
+	Line     int    `json:"line"`
+	Date     string `json:"date"`
+	Account  string `json:"account"`
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Note is a serialized note directive.
+type Note struct {
+	Index    int               `json:"index"`
+	Line     int               `json:"line"`
+	Date     string            `json:"date"`
+	Account  string            `json:"account"`
+	Comment  string            `json:"comment"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewDocument builds an export document from parsed entries in statement order.
+func NewDocument(statement Statement, txs []*parser.Transaction) *Document {
+	doc := &Document{
+		SchemaVersion: SchemaVersion,
+		Statement:     statement,
+		Transactions:  []Transaction{},
+		Balances:      []Balance{},
+	}
+	for i, tx := range txs {
+		if tx.Directive == "note" {
+			// Notes, like open work orders, may predate the statement, so
+			// they do not widen its period.
+			note := Note{Index: i, Line: tx.Line, Date: tx.Date, Account: tx.NoteAccount, Comment: tx.Narration}
+			if len(tx.Links) > 0 {
+				note.Metadata = maps.Clone(tx.Links)
+			}
+			doc.Notes = append(doc.Notes, note)
+			continue
+		}
+		if tx.Date != "" && (doc.Statement.StartDate == "" || tx.Date < doc.Statement.StartDate) {
+			doc.Statement.StartDate = tx.Date
+		}
+		if tx.Date > doc.Statement.EndDate {
+			doc.Statement.EndDate = tx.Date
+		}
+		if tx.Directive == "balance" {
+			doc.Balances = append(doc.Balances, Balance{
+				Index:    i,
+				Line:     tx.Line,
+				Date:     tx.Date,
+				Account:  tx.BalanceAccount,
+				Amount:   tx.BalanceAmount.Value,
+				Currency: tx.BalanceAmount.Currency,
+			})
+			continue
+		}
+		out := Transaction{
+			Index:     i,
+			Line:      tx.Line,
+			Date:      tx.Date,
+			Flag:      tx.Flag,
+			Payee:     tx.Payee,
+			Narration: tx.Narration,
+			Tags:      append([]string{}, tx.Tags...),
+			Metadata:  map[string]string{},
+			Postings:  make([]Posting, len(tx.Postings)),
+		}
+		for k, v := range tx.Links {
+			out.Metadata[k] = v
+		}
+		for j, p := range tx.Postings {
+			out.Postings[j] = Posting{Account: p.Account, Amount: p.Amount.Value, Currency: p.Amount.Currency}
+		}
+		doc.Transactions = append(doc.Transactions, out)
+	}
+	return doc
+}
+
+// Entries converts the document back into parser entries in statement
+// order, sorted by index. Indexes need not be contiguous, so entries can be
+// deleted from a hand-edited export, but must be unique.
+func (d *Document) Entries() ([]*parser.Transaction, error) {
+	if d.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d (want %d)", d.SchemaVersion, SchemaVersion)
+	}
+	type indexed struct {
+		index int
+		tx    *parser.Transaction
+	}
+	var placed []indexed
+	seen := map[int]bool{}
+	place := func(index int, tx *parser.Transaction) error {
+		if seen[index] {
+			return fmt.Errorf("duplicate entry index %d", index)
+		}
+		seen[index] = true
+		placed = append(placed, indexed{index, tx})
+		return nil
+	}
+
+	for _, b := range d.Balances {
+		tx := &parser.Transaction{
+			Date:           b.Date,
+			Directive:      "balance",
+			BalanceAccount: b.Account,
+			BalanceAmount:  parser.Amount{Value: b.Amount, Currency: b.Currency},
+			Line:           b.Line,
+		}
+		if err := place(b.Index, tx); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range d.Notes {
+		tx := &parser.Transaction{
+			Date:        n.Date,
+			Directive:   "note",
+			NoteAccount: n.Account,
+			Narration:   n.Comment,
+			Line:        n.Line,
+		}
+		if len(n.Metadata) > 0 {
+			tx.Links = maps.Clone(n.Metadata)
+		}
+		if err := place(n.Index, tx); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range d.Transactions {
+		tx := &parser.Transaction{
+			Date:      t.Date,
+			Flag:      t.Flag,
+			Payee:     t.Payee,
+			Narration: t.Narration,
+			Tags:      t.Tags,
+			Line:      t.Line,
+		}
+		if len(t.Metadata) > 0 {
+			tx.Links = make(map[string]string, len(t.Metadata))
+			for k, v := range t.Metadata {
+				tx.Links[k] = v
+			}
+		}
+		for _, p := range t.Postings {
+			tx.Postings = append(tx.Postings, parser.Posting{
+				Account: p.Account,
+				Amount:  parser.Amount{Value: p.Amount, Currency: p.Currency},
+			})
+		}
+		if err := place(t.Index, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(placed, func(a, b indexed) int { return cmp.Compare(a.index, b.index) })
+	entries := make([]*parser.Transaction, len(placed))
+	for i, p := range placed {
+		entries[i] = p.tx
+	}
+	return entries, nil
+}
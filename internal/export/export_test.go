@@ -0,0 +1,130 @@
+// internal/export/export_test.go
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/cloverleaf"
+	"github.com/jason-riddle/ledger-go/internal/export"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	fixturePath := filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt")
+	fixture, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs, err := cloverleaf.NewParser().Parse(string(fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs[1].Flag = parser.FlagReview
+
+	doc := export.NewDocument(export.Statement{Institution: "cloverleaf", Source: "statement.pdf"}, txs)
+	if doc.Statement.StartDate != "2025-11-01" || doc.Statement.EndDate != "2025-11-30" {
+		t.Errorf("statement period = %s to %s, want 2025-11-01 to 2025-11-30", doc.Statement.StartDate, doc.Statement.EndDate)
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteJSON(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := export.ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decoded.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, txs) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, txs)
+	}
+
+	// Deleting entries by hand leaves gaps in the indexes.
+	decoded.Transactions = append(decoded.Transactions[:2], decoded.Transactions[3:]...)
+	decoded.Balances = decoded.Balances[1:]
+	got, err = decoded.Entries()
+	if err != nil {
+		t.Fatalf("Entries() after deleting entries: %v", err)
+	}
+	if want := len(txs) - 2; len(got) != want {
+		t.Errorf("Entries() after deleting entries = %d entries, want %d", len(got), want)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Line < got[i-1].Line {
+			t.Errorf("Entries() out of statement order at %d: line %d after line %d", i, got[i].Line, got[i-1].Line)
+		}
+	}
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "unknown field", input: `{"schema_version": 1, "transactionz": []}`},
+		{name: "future version", input: `{"schema_version": 99}`},
+		{name: "duplicate index", input: `{"schema_version": 1, "balances": [{"index": 0}, {"index": 0}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := export.ReadJSON(strings.NewReader(tt.input))
+			if err == nil {
+				_, err = doc.Entries()
+			}
+			if err == nil {
+				t.Errorf("expected error for %s", tt.input)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	doc := &export.Document{
+		SchemaVersion: export.SchemaVersion,
+		Transactions: []export.Transaction{{
+			Index:    1,
+			Line:     5,
+			Date:     "2025-11-03",
+			Payee:    "Tenant",
+			Tags:     []string{"#imported"},
+			Metadata: map[string]string{"comments": "", "ref": "a,b"},
+			Postings: []export.Posting{
+				{Account: "Income:Rent", Amount: "-10.00", Currency: "USD"},
+				{Account: "Assets:Cash", Amount: "10.00", Currency: "USD"},
+			},
+		}},
+		Balances: []export.Balance{{Index: 0, Line: 2, Date: "2025-11-01", Account: "Assets:Cash", Amount: "5.00", Currency: "USD"}},
+		Notes:    []export.Note{{Index: 2, Line: 9, Date: "2025-10-13", Account: "Expenses:Repairs", Comment: "Open work order 7", Metadata: map[string]string{"work-order": "7"}}},
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteCSV(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		export.CSVHeader,
+		{"1", "0", "2", "balance", "2025-11-01", "", "", "", "", "Assets:Cash", "5.00", "USD"},
+		{"1", "1", "5", "transaction", "2025-11-03", "Tenant", "", "#imported", "comments=;ref=a,b", "Income:Rent", "-10.00", "USD"},
+		{"1", "1", "5", "transaction", "2025-11-03", "Tenant", "", "#imported", "comments=;ref=a,b", "Assets:Cash", "10.00", "USD"},
+		{"1", "2", "9", "note", "2025-10-13", "", "Open work order 7", "", "work-order=7", "Expenses:Repairs", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("WriteCSV() records = %q, want %q", records, want)
+	}
+}
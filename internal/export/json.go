@@ -0,0 +1,28 @@
+// internal/export/json.go
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteJSON writes the document as indented JSON.
+func WriteJSON(w io.Writer, doc *Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+// ReadJSON decodes a document previously written by WriteJSON, possibly hand-edited.
+// Unknown fields are rejected so typos in edited files are caught.
+func ReadJSON(r io.Reader) (*Document, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var doc Document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode export JSON: %w", err)
+	}
+	return &doc, nil
+}
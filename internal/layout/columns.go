@@ -0,0 +1,144 @@
+// internal/layout/columns.go
+package layout
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Column is a named horizontal band of a table.
+type Column struct {
+	Name       string
+	XMin, XMax float64
+	// HeadingXMin and HeadingXMax are the extent of the heading words.
+	HeadingXMin, HeadingXMax float64
+}
+
+// Columns locates the named headings in a header line and splits the page
+// width into bands: each column runs from halfway between the previous
+// heading and its own, to halfway between its own heading and the next.
+// Headings may span several words ("Cash In"); names must appear in
+// left-to-right order.
+func Columns(header Line, names ...string) ([]Column, error) {
+	type span struct{ xMin, xMax float64 }
+	spans := make([]span, len(names))
+	next := 0
+	for i, name := range names {
+		parts := strings.Fields(name)
+		found := false
+		for j := next; j+len(parts) <= len(header.Words); j++ {
+			if matchWords(header.Words[j:j+len(parts)], parts) {
+				spans[i] = span{header.Words[j].XMin, header.Words[j+len(parts)-1].XMax}
+				next = j + len(parts)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column %q not found in header %q", name, header.Text())
+		}
+	}
+
+	columns := make([]Column, len(names))
+	for i, name := range names {
+		columns[i] = Column{Name: name, XMin: math.Inf(-1), XMax: math.Inf(1), HeadingXMin: spans[i].xMin, HeadingXMax: spans[i].xMax}
+		if i > 0 {
+			columns[i].XMin = (spans[i-1].xMax + spans[i].xMin) / 2
+		}
+		if i < len(names)-1 {
+			columns[i].XMax = (spans[i].xMax + spans[i+1].xMin) / 2
+		}
+	}
+	return columns, nil
+}
+
+// FindColumns is Columns for whichever of names appear in header, in the
+// order given, for tables whose optional columns vary between statements.
+// It returns nil when none appear.
+func FindColumns(header Line, names ...string) []Column {
+	var found []string
+	for _, name := range names {
+		if _, err := Columns(header, append(found[:len(found):len(found)], name)...); err == nil {
+			found = append(found, name)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	columns, _ := Columns(header, found...)
+	return columns
+}
+
+// AlignLeft moves the boundary between each pair of adjacent named columns
+// to the start of the right-hand heading. Use it for text that is
+// left-aligned under its heading and runs past the midpoint to the next one,
+// like a name column followed by a memo column.
+func AlignLeft(columns []Column, names ...string) {
+	named := map[string]bool{}
+	for _, name := range names {
+		named[name] = true
+	}
+	for i := 1; i < len(columns); i++ {
+		if named[columns[i-1].Name] && named[columns[i].Name] {
+			columns[i-1].XMax = columns[i].HeadingXMin
+			columns[i].XMin = columns[i].HeadingXMin
+		}
+	}
+}
+
+// AlignRight moves the left boundary of each named column to width before
+// the end of its heading, for numbers right-aligned under their headings,
+// so wide text in the column to the left is not split off into it. The
+// boundary never moves left of the previous heading.
+func AlignRight(columns []Column, width float64, names ...string) {
+	for i := 1; i < len(columns); i++ {
+		if !slices.Contains(names, columns[i].Name) {
+			continue
+		}
+		boundary := math.Max(columns[i].HeadingXMax-width, columns[i-1].HeadingXMax)
+		columns[i-1].XMax = boundary
+		columns[i].XMin = boundary
+	}
+}
+
+func matchWords(words []Word, parts []string) bool {
+	for i, part := range parts {
+		if !strings.EqualFold(words[i].Text, part) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cells assigns each word of the line to the column containing its center
+// and returns the text of each column, words joined by single spaces.
+func (l Line) Cells(columns []Column) []string {
+	cells := make([][]string, len(columns))
+	for _, w := range l.Words {
+		center := w.Center()
+		for i, c := range columns {
+			if center >= c.XMin && center < c.XMax {
+				cells[i] = append(cells[i], w.Text)
+				break
+			}
+		}
+	}
+	out := make([]string, len(columns))
+	for i, words := range cells {
+		out[i] = strings.Join(words, " ")
+	}
+	return out
+}
+
+// CellsByName is Cells keyed by column name, leaving out empty cells.
+func (l Line) CellsByName(columns []Column) map[string]string {
+	cells := map[string]string{}
+	for i, text := range l.Cells(columns) {
+		if text != "" {
+			cells[columns[i].Name] = text
+		}
+	}
+	return cells
+}
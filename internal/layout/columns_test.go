@@ -0,0 +1,107 @@
+// internal/layout/columns_test.go
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnsAndCells(t *testing.T) {
+	page := FromText("" +
+		"Date        Description            Cash In    Cash Out\n" +
+		"11/05/2025  Rent payment           1,200.00\n" +
+		"11/06/2025  Plumbing repair                     350.00\n" +
+		"11/07       Repairs, unit 2B                  12.50").Pages[0]
+
+	columns, err := Columns(page.Lines[0], "Date", "Description", "Cash In", "Cash Out")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		row  int
+		want []string
+	}{
+		{row: 1, want: []string{"11/05/2025", "Rent payment", "1,200.00", ""}},
+		{row: 2, want: []string{"11/06/2025", "Plumbing repair", "", "350.00"}},
+		{row: 3, want: []string{"11/07", "Repairs, unit 2B", "", "12.50"}},
+	}
+	for _, tt := range tests {
+		if got := page.Lines[tt.row].Cells(columns); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("row %d cells = %q, want %q", tt.row, got, tt.want)
+		}
+	}
+
+	want := map[string]string{"Date": "11/06/2025", "Description": "Plumbing repair", "Cash Out": "350.00"}
+	if got := page.Lines[2].CellsByName(columns); !reflect.DeepEqual(got, want) {
+		t.Errorf("CellsByName() = %q, want %q", got, want)
+	}
+}
+
+func TestColumnsMissingHeading(t *testing.T) {
+	header := FromText("Date  Amount").Pages[0].Lines[0]
+	tests := [][]string{
+		{"Date", "Balance"},
+		{"Amount", "Date"},
+	}
+	for _, names := range tests {
+		if _, err := Columns(header, names...); err == nil {
+			t.Errorf("Columns(%q) expected error", names)
+		}
+	}
+}
+
+func TestFindColumns(t *testing.T) {
+	header := FromText("Date  Property  Account  Cash Out  Balance").Pages[0].Lines[0]
+	var got []string
+	for _, c := range FindColumns(header, "Date", "Property", "Unit", "Account", "Cash In", "Cash Out", "Amount", "Balance") {
+		got = append(got, c.Name)
+	}
+	want := []string{"Date", "Property", "Account", "Cash Out", "Balance"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindColumns() = %q, want %q", got, want)
+	}
+	if got := FindColumns(header, "Vendor"); got != nil {
+		t.Errorf("FindColumns(Vendor) = %v, want nil", got)
+	}
+}
+
+func TestAlignLeft(t *testing.T) {
+	page := FromText("" +
+		"Date      Name                       Memo                                  Amount\n" +
+		"1/1/2025  Unit 1 - Layla Noble-Davis by Layla Noble-Davis             1,550.00").Pages[0]
+
+	columns, err := Columns(page.Lines[0], "Date", "Name", "Memo", "Amount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := page.Lines[1].Cells(columns)[1], "Unit 1 - Layla"; got != want {
+		t.Errorf("centered name cell = %q, want %q", got, want)
+	}
+
+	AlignLeft(columns, "Name", "Memo")
+	want := []string{"1/1/2025", "Unit 1 - Layla Noble-Davis", "by Layla Noble-Davis", "1,550.00"}
+	if got := page.Lines[1].Cells(columns); !reflect.DeepEqual(got, want) {
+		t.Errorf("aligned cells = %q, want %q", got, want)
+	}
+}
+
+func TestAlignRight(t *testing.T) {
+	page := FromText("" +
+		"Memo                                 Amount\n" +
+		"Management Fee (9% of $1,600)    (1,150.00)").Pages[0]
+
+	columns, err := Columns(page.Lines[0], "Memo", "Amount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := page.Lines[1].Cells(columns)[1], "$1,600) (1,150.00)"; got != want {
+		t.Errorf("centered amount cell = %q, want %q", got, want)
+	}
+
+	AlignRight(columns, 12*TextCellWidth, "Amount")
+	want := []string{"Management Fee (9% of $1,600)", "(1,150.00)"}
+	if got := page.Lines[1].Cells(columns); !reflect.DeepEqual(got, want) {
+		t.Errorf("aligned cells = %q, want %q", got, want)
+	}
+}
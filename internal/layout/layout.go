@@ -0,0 +1,251 @@
+// internal/layout/layout.go
+
+// Package layout models extracted statement text as pages, lines and
+// positioned words, so parsers can reason about page breaks and table
+// columns by x-position rather than by whitespace.
+//
+// Coordinates are in PDF points with the origin at the top-left corner of
+// the page and y increasing downwards, matching `pdftotext -bbox-layout`.
+package layout
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Grid size used by FromText: one character column is TextCellWidth points
+// wide and one text line is TextLineHeight points tall (10pt Courier).
+const (
+	TextCellWidth  = 6.0
+	TextLineHeight = 12.0
+)
+
+// Document is the positioned text of a whole statement.
+type Document struct {
+	Pages []Page
+}
+
+// Page is one page of positioned text.
+type Page struct {
+	// Number is the 1-based page number.
+	Number        int
+	Width, Height float64
+	// Lines are visual rows, top of the page first.
+	Lines []Line
+}
+
+// Line is a visual row of words sharing a baseline, left to right.
+type Line struct {
+	Words []Word
+}
+
+// Word is a run of text and its bounding box.
+type Word struct {
+	Text       string
+	XMin, YMin float64
+	XMax, YMax float64
+}
+
+// Text returns the words of the line joined by single spaces.
+func (l Line) Text() string {
+	texts := make([]string, len(l.Words))
+	for i, w := range l.Words {
+		texts[i] = w.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// Baseline returns the bottom edge of the line.
+func (l Line) Baseline() float64 {
+	baseline := 0.0
+	for _, w := range l.Words {
+		baseline = math.Max(baseline, w.YMax)
+	}
+	return baseline
+}
+
+// Center returns the horizontal midpoint of the word.
+func (w Word) Center() float64 {
+	return (w.XMin + w.XMax) / 2
+}
+
+// Height returns the vertical extent of the word.
+func (w Word) Height() float64 {
+	return w.YMax - w.YMin
+}
+
+// GroupLines buckets words sharing a baseline into visual rows, top of the
+// page first, with each row sorted left to right.
+func GroupLines(words []Word) []Line {
+	sorted := make([]Word, len(words))
+	copy(sorted, words)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].YMax < sorted[j].YMax })
+
+	var lines []Line
+	var baseline, height float64
+	for _, w := range sorted {
+		if n := len(lines); n > 0 {
+			tolerance := math.Max(1, 0.3*math.Max(height, w.Height()))
+			if math.Abs(baseline-w.YMax) <= tolerance {
+				lines[n-1].Words = append(lines[n-1].Words, w)
+				continue
+			}
+		}
+		lines = append(lines, Line{Words: []Word{w}})
+		baseline, height = w.YMax, w.Height()
+	}
+	for _, line := range lines {
+		sort.SliceStable(line.Words, func(i, j int) bool { return line.Words[i].XMin < line.Words[j].XMin })
+	}
+	return lines
+}
+
+// FromText builds a document from layout text such as `pdftotext -layout`
+// output or test fixtures. Form feeds separate pages, every line becomes a
+// row (blank lines included, so line indexes match the text) and words are
+// placed on a TextCellWidth x TextLineHeight grid.
+func FromText(text string) *Document {
+	doc := &Document{}
+	for i, pageText := range strings.Split(text, "\f") {
+		page := Page{Number: i + 1}
+		rows := strings.Split(pageText, "\n")
+		for row, lineText := range rows {
+			var line Line
+			runes := []rune(lineText)
+			for col := 0; col < len(runes); {
+				if runes[col] == ' ' || runes[col] == '\t' {
+					col++
+					continue
+				}
+				end := col
+				for end < len(runes) && runes[end] != ' ' && runes[end] != '\t' {
+					end++
+				}
+				line.Words = append(line.Words, Word{
+					Text: string(runes[col:end]),
+					XMin: float64(col) * TextCellWidth,
+					XMax: float64(end) * TextCellWidth,
+					YMin: float64(row) * TextLineHeight,
+					YMax: float64(row+1) * TextLineHeight,
+				})
+				page.Width = math.Max(page.Width, float64(end)*TextCellWidth)
+				col = end
+			}
+			page.Lines = append(page.Lines, line)
+		}
+		page.Height = float64(len(rows)) * TextLineHeight
+		doc.Pages = append(doc.Pages, page)
+	}
+	return doc
+}
+
+// TextLines returns the positioned words of every line of text, indexed like
+// strings.Split(text, "\n"), for parsers that walk text line by line. Words
+// after a form feed keep their page's columns.
+func TextLines(text string) []Line {
+	var lines []Line
+	for i, page := range FromText(text).Pages {
+		rows := page.Lines
+		if i > 0 {
+			// A page's first row continues the line holding the form feed.
+			last := &lines[len(lines)-1]
+			last.Words = append(last.Words, rows[0].Words...)
+			rows = rows[1:]
+		}
+		lines = append(lines, rows...)
+	}
+	return lines
+}
+
+// Text renders the document as layout text: words are placed on a character
+// grid sized from the median glyph width so columns stay separated by runs
+// of spaces, vertical gaps become blank lines and every page ends with a
+// form feed, in the style of `pdftotext -layout`.
+func (d *Document) Text() string {
+	var b strings.Builder
+	for _, page := range d.Pages {
+		b.WriteString(page.Text())
+		b.WriteString("\f")
+	}
+	return b.String()
+}
+
+// Text renders one page as layout text. See Document.Text.
+func (p Page) Text() string {
+	var lines []Line
+	for _, line := range p.Lines {
+		if len(line.Words) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	cell := p.cellWidth()
+	minX := math.Inf(1)
+	for _, line := range lines {
+		minX = math.Min(minX, line.Words[0].XMin)
+	}
+	lineHeight := lineSpacing(lines)
+
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			gap := line.Baseline() - lines[i-1].Baseline()
+			for blanks := int(math.Round(gap/lineHeight)) - 1; blanks > 0; blanks-- {
+				b.WriteString("\n")
+			}
+		}
+		col := 0
+		for j, w := range line.Words {
+			target := int(math.Round((w.XMin - minX) / cell))
+			spaces := target
+			if j > 0 {
+				spaces = max(1, target-col)
+			}
+			b.WriteString(strings.Repeat(" ", spaces))
+			b.WriteString(w.Text)
+			col += spaces + len([]rune(w.Text))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// cellWidth estimates the width of one character column as the median
+// per-character word width.
+func (p Page) cellWidth() float64 {
+	var widths []float64
+	for _, line := range p.Lines {
+		for _, w := range line.Words {
+			if n := len([]rune(w.Text)); n > 0 && w.XMax > w.XMin {
+				widths = append(widths, (w.XMax-w.XMin)/float64(n))
+			}
+		}
+	}
+	if len(widths) == 0 {
+		return TextCellWidth
+	}
+	sort.Float64s(widths)
+	return widths[len(widths)/2]
+}
+
+// lineSpacing estimates the distance between consecutive baselines: the
+// smallest gap that is not an overlapping superscript, capped at the usual
+// 1.2x font leading so pages that are all double-spaced keep their blank lines.
+func lineSpacing(lines []Line) float64 {
+	height := lines[0].Words[0].Height()
+	if height <= 0 {
+		height = TextLineHeight / 1.2
+	}
+	spacing := 1.2 * height
+	for i := 1; i < len(lines); i++ {
+		gap := lines[i].Baseline() - lines[i-1].Baseline()
+		if gap >= 0.8*lines[i].Words[0].Height() && gap < spacing {
+			spacing = gap
+		}
+	}
+	return spacing
+}
@@ -0,0 +1,87 @@
+// internal/layout/layout_test.go
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromText(t *testing.T) {
+	doc := FromText("Date   Amount\n\n11/05  $10.00\fPage two")
+
+	if len(doc.Pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(doc.Pages))
+	}
+	first := doc.Pages[0]
+	if len(first.Lines) != 3 {
+		t.Fatalf("got %d lines on page 1, want 3 (blank lines kept)", len(first.Lines))
+	}
+	if got := first.Lines[1].Words; len(got) != 0 {
+		t.Errorf("blank line has words %v", got)
+	}
+	want := Word{Text: "$10.00", XMin: 42, XMax: 78, YMin: 24, YMax: 36}
+	if got := first.Lines[2].Words[1]; got != want {
+		t.Errorf("word = %+v, want %+v", got, want)
+	}
+	if doc.Pages[1].Number != 2 || doc.Pages[1].Lines[0].Text() != "Page two" {
+		t.Errorf("page 2 = %+v", doc.Pages[1])
+	}
+}
+
+func TestTextLines(t *testing.T) {
+	text := "Date   Amount\n11/05  $10.00\n\fPage two\nend"
+	lines := TextLines(text)
+
+	var got []string
+	for _, line := range lines {
+		got = append(got, line.Text())
+	}
+	want := []string{"Date Amount", "11/05 $10.00", "Page two", "end"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TextLines() = %q, want %q", got, want)
+	}
+	if x := lines[2].Words[0].XMin; x != 0 {
+		t.Errorf("word after form feed at x = %v, want 0", x)
+	}
+}
+
+func TestDocumentTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "single page", text: "Date   Amount\n11/05  $10.00\n"},
+		{name: "blank lines", text: "Header\n\n\n  Indented   row\n"},
+		{name: "pages", text: "One\n\fTwo  2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := FromText(tt.text).Text(), tt.text+"\f"; got != want {
+				t.Errorf("Text() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGroupLines(t *testing.T) {
+	words := []Word{
+		{Text: "b", XMin: 50, XMax: 56, YMin: 30, YMax: 40},
+		{Text: "a", XMin: 10, XMax: 16, YMin: 10, YMax: 20},
+		{Text: "c", XMin: 10, XMax: 16, YMin: 31, YMax: 41},
+		{Text: "d", XMin: 30, XMax: 36, YMin: 10, YMax: 20},
+	}
+
+	var got [][]string
+	for _, line := range GroupLines(words) {
+		var texts []string
+		for _, w := range line.Words {
+			texts = append(texts, w.Text)
+		}
+		got = append(got, texts)
+	}
+	want := [][]string{{"a", "d"}, {"c", "b"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupLines() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,169 @@
+// internal/normalize/normalize.go
+package normalize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// DateLayout is the layout of normalized dates.
+const DateLayout = "2006-01-02"
+
+// currencyMarks are stripped from either end of an amount.
+var currencyMarks = []string{"USD", "$", "€", "£", "¥"}
+
+var digitsRe = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// Amount converts a statement amount to a plain signed decimal such as
+// -1053.10. It accepts:
+//
+//   - currency symbols and a USD code before or after the number, e.g. $1.00
+//   - thousands separators and stray spaces from extraction, e.g. 1 ,150.00
+//   - negatives written (1.00), -1.00 or 1.00-, with the symbol inside or
+//     outside the sign, e.g. $(1.00) or -$1.00
+//   - CR and DR suffixes: a credit stays positive and a debit is negative
+//
+// Zero is never negative. Malformed input returns an error wrapping
+// parser.ErrInvalidAmount.
+func Amount(amount string) (string, error) {
+	invalid := fmt.Errorf("%w %q", parser.ErrInvalidAmount, strings.TrimSpace(amount))
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, amount)
+
+	negatives := 0
+	upper := strings.ToUpper(s)
+	switch {
+	case strings.HasSuffix(upper, "DR"):
+		negatives++
+		s = s[:len(s)-2]
+	case strings.HasSuffix(upper, "CR"):
+		s = s[:len(s)-2]
+	}
+	// Signs and currency marks may nest in either order, e.g. $(1.00) and
+	// ($1.00), so peel them until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for _, mark := range currencyMarks {
+			if rest, ok := strings.CutPrefix(s, mark); ok {
+				s, changed = rest, true
+			}
+			if rest, ok := strings.CutSuffix(s, mark); ok {
+				s, changed = rest, true
+			}
+		}
+		if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+			s, changed = s[1:len(s)-1], true
+			negatives++
+		}
+		if rest, ok := strings.CutPrefix(s, "-"); ok {
+			s, changed = rest, true
+			negatives++
+		}
+		if rest, ok := strings.CutSuffix(s, "-"); ok {
+			s, changed = rest, true
+			negatives++
+		}
+	}
+	if negatives > 1 {
+		return "", invalid
+	}
+
+	s = strings.ReplaceAll(s, ",", "")
+	if !digitsRe.MatchString(s) {
+		return "", invalid
+	}
+	if negatives == 1 && strings.Trim(s, "0.") != "" {
+		return "-" + s, nil
+	}
+	return s, nil
+}
+
+// Negate flips the sign of an amount returned by Amount. Zero stays
+// unsigned.
+func Negate(amount string) string {
+	if rest, ok := strings.CutPrefix(amount, "-"); ok {
+		return rest
+	}
+	if strings.Trim(amount, "0.") == "" {
+		return amount
+	}
+	return "-" + amount
+}
+
+// fullDateLayouts are the dated layouts Date accepts, after spaces are
+// removed.
+var fullDateLayouts = []string{"1-2-2006", "1/2/2006", DateLayout}
+
+// Date converts a statement date in MM-DD-YYYY, M/D/YYYY or YYYY-MM-DD to
+// YYYY-MM-DD. Spaces are ignored, so "11 / 5 / 2025" is accepted. Malformed
+// or impossible dates return an error wrapping parser.ErrInvalidDate.
+func Date(date string) (string, error) {
+	clean := removeSpaces(date)
+	for _, layout := range fullDateLayouts {
+		if t, err := time.Parse(layout, clean); err == nil {
+			return t.Format(DateLayout), nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", parser.ErrInvalidDate, strings.TrimSpace(date))
+}
+
+// InferDate is Date that also accepts MM/DD and MM-DD without a year,
+// choosing the latest year that puts the date on or before ref, usually the
+// statement's closing date.
+func InferDate(date string, ref time.Time) (string, error) {
+	if full, err := Date(date); err == nil {
+		return full, nil
+	}
+	clean := removeSpaces(date)
+	refDay := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, time.UTC)
+	for _, layout := range []string{"1/2", "1-2"} {
+		t, err := time.Parse(layout, clean)
+		if err != nil {
+			continue
+		}
+		// Step back until the date exists (February 29 needs a leap year)
+		// and is not after ref.
+		for year := ref.Year(); ; year-- {
+			inferred := time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			if inferred.Day() == t.Day() && !inferred.After(refDay) {
+				return inferred.Format(DateLayout), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w %q", parser.ErrInvalidDate, strings.TrimSpace(date))
+}
+
+// streetSuffixes abbreviates the street types in property slugs.
+var streetSuffixes = map[string]string{
+	"Avenue": "Ave",
+	"Street": "St",
+	"Road":   "Rd",
+	"Drive":  "Dr",
+}
+
+// PropertySlug turns a street address into the account name component
+// used for the property, like 206-Hoover-Ave for "206 Hoover Avenue".
+// Every parser names property accounts with it so postings, balances and
+// notes for one property share the same accounts.
+func PropertySlug(street string) string {
+	parts := strings.Fields(street)
+	for i, part := range parts {
+		if short, ok := streetSuffixes[part]; ok {
+			parts[i] = short
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+func removeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
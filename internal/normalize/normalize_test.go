@@ -0,0 +1,212 @@
+// internal/normalize/normalize_test.go
+package normalize
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1,053.10", want: "1053.10"},
+		{in: "$1,600.00", want: "1600.00"},
+		{in: " $ (1,053.10) ", want: "-1053.10"},
+		{in: "($338.81)", want: "-338.81"},
+		{in: "$(338.81)", want: "-338.81"},
+		{in: "-1,150.00", want: "-1150.00"},
+		{in: "-$5.00", want: "-5.00"},
+		{in: "$-5.00", want: "-5.00"},
+		{in: "5.00-", want: "-5.00"},
+		{in: "$1 ,1 50.00", want: "1150.00"},
+		{in: "210.00 CR", want: "210.00"},
+		{in: "210.00DR", want: "-210.00"},
+		{in: "210.00 dr", want: "-210.00"},
+		{in: "€12.50", want: "12.50"},
+		{in: "12.50 USD", want: "12.50"},
+		{in: "(0.00)", want: "0.00"},
+		{in: "-0", want: "0"},
+		{in: "42", want: "42"},
+		{in: "", wantErr: true},
+		{in: "$", wantErr: true},
+		{in: "(-5.00)", wantErr: true},
+		{in: "5.00- DR", wantErr: true},
+		{in: "(5.00", wantErr: true},
+		{in: "5.0.0", wantErr: true},
+		{in: "1,2x3.00", wantErr: true},
+		{in: "8.625%", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Amount(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, parser.ErrInvalidAmount) {
+				t.Errorf("Amount(%q) = %q, %v, want ErrInvalidAmount", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Amount(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestNegate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "1053.10", want: "-1053.10"},
+		{in: "-1053.10", want: "1053.10"},
+		{in: "0.00", want: "0.00"},
+		{in: "0", want: "0"},
+	}
+	for _, tt := range tests {
+		if got := Negate(tt.in); got != tt.want {
+			t.Errorf("Negate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPropertySlug(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "206 Hoover Avenue", want: "206-Hoover-Ave"},
+		{in: "206 Hoover Ave", want: "206-Hoover-Ave"},
+		{in: " 2943  Butterfly Palm ", want: "2943-Butterfly-Palm"},
+		{in: "12 Oak Street", want: "12-Oak-St"},
+		{in: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := PropertySlug(tt.in); got != tt.want {
+			t.Errorf("PropertySlug(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "11-01-2025", want: "2025-11-01"},
+		{in: "1-5-2025", want: "2025-01-05"},
+		{in: "1/3/2025", want: "2025-01-03"},
+		{in: "12/31/2025", want: "2025-12-31"},
+		{in: "11 / 5 / 2025", want: "2025-11-05"},
+		{in: "1 /1 /2025", want: "2025-01-01"},
+		{in: "2025-11-30", want: "2025-11-30"},
+		{in: "02/29/2024", want: "2024-02-29"},
+		{in: "02/29/2025", wantErr: true},
+		{in: "13-01-2025", wantErr: true},
+		{in: "11-35-2025", wantErr: true},
+		{in: "11/05", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Date(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, parser.ErrInvalidDate) {
+				t.Errorf("Date(%q) = %q, %v, want ErrInvalidDate", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Date(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestInferDate(t *testing.T) {
+	ref := time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		ref     time.Time
+		want    string
+		wantErr bool
+	}{
+		{in: "11/02", ref: ref, want: "2023-11-02"},
+		{in: "11/14", ref: ref, want: "2023-11-14"},
+		{in: "10/16", ref: ref, want: "2023-10-16"},
+		{in: "12/28", ref: ref, want: "2022-12-28"},
+		{in: "1 / 5", ref: ref, want: "2023-01-05"},
+		{in: "12-28", ref: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), want: "2023-12-28"},
+		{in: "02/29", ref: ref, want: "2020-02-29"},
+		{in: "11/5/2025", ref: ref, want: "2025-11-05"},
+		{in: "11/31", ref: ref, wantErr: true},
+		{in: "13/01", ref: ref, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := InferDate(tt.in, tt.ref)
+		if tt.wantErr {
+			if !errors.Is(err, parser.ErrInvalidDate) {
+				t.Errorf("InferDate(%q) = %q, %v, want ErrInvalidDate", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("InferDate(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+var normalizedAmountRe = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+func FuzzAmount(f *testing.F) {
+	for _, seed := range []string{"1,053.10", "$ (1,053.10)", "-$5.00", "5.00-", "$1 ,1 50.00", "210.00 DR", "210.00CR", "€12.50", "(0.00)", "(-5.00)", "$"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		got, err := Amount(in)
+		if err != nil {
+			if !errors.Is(err, parser.ErrInvalidAmount) {
+				t.Fatalf("Amount(%q) error = %v, want ErrInvalidAmount", in, err)
+			}
+			return
+		}
+		if !normalizedAmountRe.MatchString(got) {
+			t.Fatalf("Amount(%q) = %q, not a plain decimal", in, got)
+		}
+		// Normalized amounts normalize to themselves.
+		if again, err := Amount(got); err != nil || again != got {
+			t.Fatalf("Amount(%q) = %q, %v, want %q", got, again, err, got)
+		}
+	})
+}
+
+func FuzzDate(f *testing.F) {
+	for _, seed := range []string{"11-01-2025", "1/3/2025", "11 / 5 / 2025", "2025-11-30", "02/29/2024", "11/05", "13-01-2025"} {
+		f.Add(seed)
+	}
+	ref := time.Date(2025, 12, 11, 0, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, in string) {
+		got, err := InferDate(in, ref)
+		if err != nil {
+			if !errors.Is(err, parser.ErrInvalidDate) {
+				t.Fatalf("InferDate(%q) error = %v, want ErrInvalidDate", in, err)
+			}
+			if _, err := Date(in); err == nil {
+				t.Fatalf("Date(%q) accepted input InferDate rejected", in)
+			}
+			return
+		}
+		if _, err := time.Parse(DateLayout, got); err != nil {
+			t.Fatalf("InferDate(%q) = %q, not YYYY-MM-DD", in, got)
+		}
+		if again, err := Date(got); err != nil || again != got {
+			t.Fatalf("Date(%q) = %q, %v, want %q", got, again, err, got)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+// internal/parser/coverage.go
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LineStatus says how a parser handled one line of statement text.
+type LineStatus int
+
+const (
+	// LineOutside lines lie outside the sections transactions are read from.
+	LineOutside LineStatus = iota
+	// LineConsumed lines produced an entry or set state used by later
+	// entries, such as the current property.
+	LineConsumed
+	// LineIgnored lines matched a rule for content that carries no entry,
+	// such as column headers, subtotals and wrapped text.
+	LineIgnored
+	// LineUnrecognized lines are inside a transaction section but matched
+	// no rule, so whatever they hold was dropped.
+	LineUnrecognized
+)
+
+// String returns the status name used in reports.
+func (s LineStatus) String() string {
+	switch s {
+	case LineOutside:
+		return "outside"
+	case LineConsumed:
+		return "consumed"
+	case LineIgnored:
+		return "ignored"
+	case LineUnrecognized:
+		return "unrecognized"
+	default:
+		return fmt.Sprintf("LineStatus(%d)", int(s))
+	}
+}
+
+// LineReport is the classification of one statement line.
+type LineReport struct {
+	// Line is the 1-based line number in the statement text.
+	Line    int
+	Text    string
+	Section string
+	Status  LineStatus
+	// Rule names the parser rule that consumed or ignored the line.
+	Rule string
+}
+
+// Coverage records how a parser classified every line of a statement.
+type Coverage struct {
+	Lines []LineReport
+}
+
+// Add records the classification of the 1-based line.
+func (c *Coverage) Add(line int, text, section string, status LineStatus, rule string) {
+	c.Lines = append(c.Lines, LineReport{Line: line, Text: text, Section: section, Status: status, Rule: rule})
+}
+
+// Unrecognized returns the lines no rule accounted for.
+func (c *Coverage) Unrecognized() []LineReport {
+	var lines []LineReport
+	for _, line := range c.Lines {
+		if line.Status == LineUnrecognized {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Err returns an *UnrecognizedError listing the unrecognized lines, or nil
+// when every line in a transaction section was accounted for.
+func (c *Coverage) Err() error {
+	if lines := c.Unrecognized(); len(lines) > 0 {
+		return &UnrecognizedError{Lines: lines}
+	}
+	return nil
+}
+
+// UnrecognizedError reports statement lines inside transaction sections that
+// no parser rule matched.
+type UnrecognizedError struct {
+	Lines []LineReport
+}
+
+func (e *UnrecognizedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d unrecognized line(s) in transaction sections:", len(e.Lines))
+	for _, line := range e.Lines {
+		fmt.Fprintf(&b, "\n  line %d: %s", line.Line, strings.TrimSpace(line.Text))
+	}
+	return b.String()
+}
+
+var amountRe = regexp.MustCompile(`\d\.\d{2}\b`)
+
+// HasAmount reports whether line contains a money amount such as 1,200.00.
+// Parsers use it to tell wrapped text, which can be ignored, from rows that
+// look like entries but matched no rule.
+func HasAmount(line string) bool {
+	return amountRe.MatchString(line)
+}
+
+// SectionTotals counts the classified lines of one section.
+type SectionTotals struct {
+	Section      string
+	Consumed     int
+	Ignored      int
+	Unrecognized int
+}
+
+// Lines returns the number of classified lines in the section.
+func (t SectionTotals) Lines() int {
+	return t.Consumed + t.Ignored + t.Unrecognized
+}
+
+// Totals returns per-section counts in the order sections first appear.
+// Outside lines are not counted; consumed lines outside any section, like
+// balances some statements print before their details, count under "".
+func (c *Coverage) Totals() []SectionTotals {
+	var totals []SectionTotals
+	index := map[string]int{}
+	for _, line := range c.Lines {
+		if line.Status == LineOutside {
+			continue
+		}
+		i, ok := index[line.Section]
+		if !ok {
+			i = len(totals)
+			index[line.Section] = i
+			totals = append(totals, SectionTotals{Section: line.Section})
+		}
+		switch line.Status {
+		case LineConsumed:
+			totals[i].Consumed++
+		case LineIgnored:
+			totals[i].Ignored++
+		case LineUnrecognized:
+			totals[i].Unrecognized++
+		}
+	}
+	return totals
+}
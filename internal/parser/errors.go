@@ -0,0 +1,79 @@
+// internal/parser/errors.go
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidAmount is the cause of a ParseError for a malformed amount.
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrInvalidDate is the cause of a ParseError for a malformed or
+	// impossible date.
+	ErrInvalidDate = errors.New("invalid date")
+)
+
+// ParseError reports a statement line a parser recognized but could not
+// turn into an entry, such as a transaction row with an impossible date.
+type ParseError struct {
+	Institution string
+	// Line is the 1-based line in the statement text.
+	Line int
+	// Column is the 1-based byte offset of the offending text in the line,
+	// or 0 when unknown.
+	Column int
+	// Text is the raw statement line.
+	Text string
+	Err  error
+}
+
+// NewParseError returns a ParseError for field within line, which is line
+// number lineNum of the statement text.
+func NewParseError(institution string, lineNum int, line, field string, err error) *ParseError {
+	column := 0
+	if i := strings.Index(line, field); field != "" && i >= 0 {
+		column = i + 1
+	}
+	return &ParseError{Institution: institution, Line: lineNum, Column: column, Text: line, Err: err}
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("%s: line %d:%d: %v", e.Institution, e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("%s: line %d: %v", e.Institution, e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors collects every ParseError from one statement, so a single bad
+// line does not hide the rest.
+type ParseErrors []*ParseError
+
+// Err returns e as an error, or nil when it is empty.
+func (e ParseErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
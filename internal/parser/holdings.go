@@ -0,0 +1,50 @@
+// internal/parser/holdings.go
+package parser
+
+import "strings"
+
+// Accounts for funds a property manager holds on the owner's behalf.
+const (
+	depositsPrefix = "Liabilities:Security-Deposits-Owed:"
+	reservesPrefix = "Assets:Property-Reserves:"
+)
+
+// DepositAccount is the liability for tenant security deposits the
+// property manager holds for a property.
+func DepositAccount(property string) string {
+	return depositsPrefix + property
+}
+
+// ReserveAccount holds the cash the property manager keeps in reserve for
+// a property. Contributions move cash from the management account into it
+// and releases move it back.
+func ReserveAccount(property string) string {
+	return reservesPrefix + property
+}
+
+// DropUnpostedHoldings removes balance assertions on deposit and reserve
+// accounts that no transaction in txs posts to.
+//
+// Statements report the deposits and reserves held, but those funds were
+// usually collected before the ledger began, so an import that never
+// touches the account would assert a balance nothing in the ledger
+// explains. Assertions are kept only for accounts the import posts to; the
+// first import posting to one needs the amount held before it booked once
+// to the account, for example with a pad from Equity:Opening-Balances.
+func DropUnpostedHoldings(txs []*Transaction) []*Transaction {
+	posted := map[string]bool{}
+	for _, tx := range txs {
+		for _, p := range tx.Postings {
+			posted[p.Account] = true
+		}
+	}
+	kept := txs[:0:0]
+	for _, tx := range txs {
+		holding := strings.HasPrefix(tx.BalanceAccount, depositsPrefix) || strings.HasPrefix(tx.BalanceAccount, reservesPrefix)
+		if tx.Directive == "balance" && holding && !posted[tx.BalanceAccount] {
+			continue
+		}
+		kept = append(kept, tx)
+	}
+	return kept
+}
@@ -1,18 +1,60 @@
 // internal/parser/interface.go
 package parser
 
+import "context"
+
+// Transaction flags. The zero value of Transaction.Flag means FlagCleared.
+const (
+	FlagCleared = "*"
+	FlagReview  = "!"
+)
+
+// ReviewKey is the metadata key holding why an entry needs review.
+const ReviewKey = "review"
+
+// SuspenseAccount is where parsers book amounts no account rule matched,
+// flagged for review with UnmatchedReason. Importers may rebook them to
+// another account.
+const SuspenseAccount = "Expenses:Unidentified"
+
+// UnmatchedReason is the review reason for entries booked to the suspense
+// account.
+const UnmatchedReason = "no account rule matches the description"
+
+// NoPropertyReason is the review reason for entries booked to the suspense
+// account because their line needs a property and names none.
+const NoPropertyReason = "no property for the line"
+
 // Transaction represents a Beancount transaction.
 type Transaction struct {
 	Date      string
 	Directive string
+	// Flag is FlagCleared (or empty) for confirmed entries and FlagReview
+	// for entries that need a human to check them.
+	Flag string
 	// BalanceAccount and BalanceAmount are used when Directive == "balance".
 	BalanceAccount string
 	BalanceAmount  Amount
-	Payee          string
-	Narration      string
-	Tags           []string
-	Links          map[string]string
-	Postings       []Posting
+	// NoteAccount is used when Directive == "note"; Narration holds the
+	// note's text and Links its metadata.
+	NoteAccount string
+	Payee       string
+	Narration   string
+	Tags        []string
+	Links       map[string]string
+	Postings    []Posting
+	// Line is the 1-based line in the statement text the entry came from,
+	// or 0 when unknown.
+	Line int
+}
+
+// MarkForReview flags tx for review and records why in its metadata.
+func (tx *Transaction) MarkForReview(reason string) {
+	tx.Flag = FlagReview
+	if tx.Links == nil {
+		tx.Links = map[string]string{}
+	}
+	tx.Links[ReviewKey] = reason
 }
 
 // Posting represents a transaction posting.
@@ -30,4 +72,9 @@ type Amount struct {
 // Parser defines the interface for parsing statement text into transactions.
 type Parser interface {
 	Parse(text string) ([]*Transaction, error)
+	// ParseContext is Parse that stops with ctx.Err() once ctx is done.
+	ParseContext(ctx context.Context, text string) ([]*Transaction, error)
+	// ParseCoverage is ParseContext that also reports how every line of
+	// text was classified.
+	ParseCoverage(ctx context.Context, text string) ([]*Transaction, *Coverage, error)
 }
@@ -0,0 +1,33 @@
+// internal/parser/summary.go
+package parser
+
+import "context"
+
+// SummaryTotal is a total printed in a statement's summary, such as total
+// income or the income of one property, for checking against the entries
+// parsed from the statement's details.
+type SummaryTotal struct {
+	// Label is the summary row as printed, e.g. "Total Income (+)".
+	Label string
+	// Property is the account component naming the property the total
+	// covers, like 206-Hoover-Ave, or "" for all properties.
+	Property string
+	// Account is the account, or parent of the accounts, whose postings
+	// the total covers. It is empty for totals with no entries to compare,
+	// like reserves the property manager holds back.
+	Account string
+	// Amount is the expected sum of the postings, signed like a posting:
+	// income totals are negative. Totals without an Account keep the sign
+	// the statement prints.
+	Amount string
+	// Line is the 1-based line the total was read from.
+	Line int
+}
+
+// Summarizer is implemented by parsers for statements that print summary
+// totals.
+type Summarizer interface {
+	// ParseSummary returns the statement's summary totals in the order
+	// they are printed.
+	ParseSummary(ctx context.Context, text string) ([]SummaryTotal, error)
+}
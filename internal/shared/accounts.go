@@ -0,0 +1,210 @@
+// internal/shared/accounts.go
+package shared
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// AccountsMode controls what happens to entries that use accounts the
+// accounts header does not allow.
+type AccountsMode int
+
+const (
+	// AccountsFail rejects the entries.
+	AccountsFail AccountsMode = iota
+	// AccountsWarn logs each problem and keeps the entries.
+	AccountsWarn
+	// AccountsOpen generates open directives for accounts the header does
+	// not open; other problems are still rejected.
+	AccountsOpen
+)
+
+// String returns the flag value for the mode.
+func (m AccountsMode) String() string {
+	switch m {
+	case AccountsFail:
+		return "fail"
+	case AccountsWarn:
+		return "warn"
+	case AccountsOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("AccountsMode(%d)", int(m))
+	}
+}
+
+// ParseAccountsMode converts a flag value into an AccountsMode.
+func ParseAccountsMode(value string) (AccountsMode, error) {
+	switch value {
+	case "", "fail":
+		return AccountsFail, nil
+	case "warn":
+		return AccountsWarn, nil
+	case "open":
+		return AccountsOpen, nil
+	default:
+		return 0, fmt.Errorf("unknown missing accounts mode %q (want fail, warn or open)", value)
+	}
+}
+
+// OpenAccount is an account opened by an open directive.
+type OpenAccount struct {
+	Account string
+	Opened  string
+	// Closed is the date of the account's close directive, or "".
+	Closed string
+	// Currencies lists the allowed currencies; empty allows any.
+	Currencies []string
+}
+
+var (
+	// headerOpenRe matches an open directive and its currency constraints.
+	headerOpenRe  = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+open\s+(\S+)(?:\s+([A-Z][A-Z0-9'._-]*(?:\s*,\s*[A-Z][A-Z0-9'._-]*)*))?`)
+	headerCloseRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+close\s+(\S+)`)
+)
+
+// LoadAccountsHeader reads the open and close directives in a Beancount
+// accounts header and the files it includes, keyed by account.
+func LoadAccountsHeader(path string) (map[string]*OpenAccount, error) {
+	accounts := map[string]*OpenAccount{}
+	err := walkLedger(path, map[string]bool{}, func(_ string, _ int, line string) {
+		if m := headerOpenRe.FindStringSubmatch(line); m != nil {
+			account := &OpenAccount{Account: m[2], Opened: m[1]}
+			for _, currency := range strings.Split(m[3], ",") {
+				if currency = strings.TrimSpace(currency); currency != "" {
+					account.Currencies = append(account.Currencies, currency)
+				}
+			}
+			accounts[account.Account] = account
+		} else if m := headerCloseRe.FindStringSubmatch(line); m != nil {
+			if account := accounts[m[2]]; account != nil {
+				account.Closed = m[1]
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Loaded accounts header", "path", path, "accounts", len(accounts))
+	return accounts, nil
+}
+
+// AccountIssue is an entry using an account the accounts header does not
+// allow on the entry's date or in the entry's currency.
+type AccountIssue struct {
+	Line     int
+	Date     string
+	Account  string
+	Currency string
+	// Unopened is set when the header never opens the account.
+	Unopened bool
+	Problem  string
+}
+
+func (i AccountIssue) Error() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s %s: %s", i.Line, i.Date, i.Account, i.Problem)
+	}
+	return fmt.Sprintf("%s %s: %s", i.Date, i.Account, i.Problem)
+}
+
+// CheckAccounts checks that every posting, balance and note account in txs
+// is open on the entry's date and that amounts use one of the account's
+// currencies, listing each problem once per entry and account.
+func CheckAccounts(txs []*parser.Transaction, accounts map[string]*OpenAccount) []AccountIssue {
+	var issues []AccountIssue
+	for _, tx := range txs {
+		var uses []parser.Posting
+		switch tx.Directive {
+		case "balance":
+			uses = []parser.Posting{{Account: tx.BalanceAccount, Amount: tx.BalanceAmount}}
+		case "note":
+			uses = []parser.Posting{{Account: tx.NoteAccount}}
+		default:
+			uses = tx.Postings
+		}
+		reported := map[string]bool{}
+		for _, use := range uses {
+			issue := AccountIssue{Line: tx.Line, Date: tx.Date, Account: use.Account, Currency: use.Amount.Currency}
+			open := accounts[use.Account]
+			switch {
+			case open == nil:
+				issue.Unopened = true
+				issue.Problem = "account is not opened in the accounts header"
+			case tx.Date < open.Opened:
+				issue.Problem = fmt.Sprintf("account opens on %s, after the entry", open.Opened)
+			case open.Closed != "" && tx.Date > open.Closed:
+				issue.Problem = fmt.Sprintf("account was closed on %s", open.Closed)
+			case use.Amount.Currency != "" && len(open.Currencies) > 0 && !slices.Contains(open.Currencies, use.Amount.Currency):
+				issue.Problem = fmt.Sprintf("currency %s is not allowed (want %s)", use.Amount.Currency, strings.Join(open.Currencies, ", "))
+			default:
+				continue
+			}
+			if key := issue.Account + "\x00" + issue.Problem; !reported[key] {
+				reported[key] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+	slog.Debug("Checked accounts against header", "entries", len(txs), "issues", len(issues))
+	return issues
+}
+
+// MissingOpens returns open directives for the unopened accounts in issues:
+// each opens on the earliest date it is used, with the currencies it is used
+// in. They are sorted by account.
+func MissingOpens(issues []AccountIssue) []OpenAccount {
+	opens := map[string]*OpenAccount{}
+	for _, issue := range issues {
+		if !issue.Unopened {
+			continue
+		}
+		open := opens[issue.Account]
+		if open == nil {
+			open = &OpenAccount{Account: issue.Account, Opened: issue.Date}
+			opens[issue.Account] = open
+		}
+		if issue.Date < open.Opened {
+			open.Opened = issue.Date
+		}
+		if issue.Currency != "" && !slices.Contains(open.Currencies, issue.Currency) {
+			open.Currencies = append(open.Currencies, issue.Currency)
+		}
+	}
+	var missing []OpenAccount
+	for _, open := range opens {
+		slices.Sort(open.Currencies)
+		missing = append(missing, *open)
+	}
+	slices.SortFunc(missing, func(a, b OpenAccount) int { return strings.Compare(a.Account, b.Account) })
+	return missing
+}
+
+// WriteOpens renders open directives in the given format: Beancount open
+// directives, or account directives for ledger and hledger.
+func WriteOpens(w io.Writer, opens []OpenAccount, format OutputFormat) error {
+	var b strings.Builder
+	for _, open := range opens {
+		switch format {
+		case OutputBeancount:
+			fmt.Fprintf(&b, "%s open %s", open.Opened, open.Account)
+			if len(open.Currencies) > 0 {
+				fmt.Fprintf(&b, " %s", strings.Join(open.Currencies, ","))
+			}
+			b.WriteString("\n")
+		case OutputLedger, OutputHledger:
+			fmt.Fprintf(&b, "account %s\n", open.Account)
+		default:
+			return fmt.Errorf("unsupported output format %v", format)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
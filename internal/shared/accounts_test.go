@@ -0,0 +1,132 @@
+// internal/shared/accounts_test.go
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestParseAccountsMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    AccountsMode
+		wantErr bool
+	}{
+		{value: "", want: AccountsFail},
+		{value: "fail", want: AccountsFail},
+		{value: "warn", want: AccountsWarn},
+		{value: "open", want: AccountsOpen},
+		{value: "create", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseAccountsMode(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccountsMode(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAccountsMode(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAccountsHeader(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"header.bean": "option \"operating_currency\" \"USD\"\ninclude \"closed.bean\"\n\n" +
+			"1970-01-01 open Assets:PM\n" +
+			"2024-01-01 open Assets:Brokerage USD, VTI \"FIFO\"\n",
+		"closed.bean": "2020-01-01 open Liabilities:Old-Loan USD\n2023-06-30 close Liabilities:Old-Loan\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := LoadAccountsHeader(filepath.Join(dir, "header.bean"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*OpenAccount{
+		"Assets:PM":            {Account: "Assets:PM", Opened: "1970-01-01"},
+		"Assets:Brokerage":     {Account: "Assets:Brokerage", Opened: "2024-01-01", Currencies: []string{"USD", "VTI"}},
+		"Liabilities:Old-Loan": {Account: "Liabilities:Old-Loan", Opened: "2020-01-01", Closed: "2023-06-30", Currencies: []string{"USD"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadAccountsHeader() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckAccounts(t *testing.T) {
+	accounts := map[string]*OpenAccount{
+		"Assets:PM":            {Account: "Assets:PM", Opened: "1970-01-01"},
+		"Expenses:Repairs":     {Account: "Expenses:Repairs", Opened: "2025-01-01", Currencies: []string{"USD"}},
+		"Liabilities:Old-Loan": {Account: "Liabilities:Old-Loan", Opened: "2020-01-01", Closed: "2023-06-30"},
+	}
+	usd := func(value string) parser.Amount { return parser.Amount{Value: value, Currency: "USD"} }
+	txs := []*parser.Transaction{
+		{Line: 10, Date: "2025-03-01", Postings: []parser.Posting{{Account: "Assets:PM", Amount: usd("-50.00")}, {Account: "Expenses:Repairs", Amount: usd("50.00")}}},
+		{Line: 11, Date: "2024-12-31", Postings: []parser.Posting{{Account: "Assets:PM", Amount: usd("-50.00")}, {Account: "Expenses:Repairs", Amount: usd("50.00")}}},
+		{Line: 12, Date: "2025-03-02", Postings: []parser.Posting{{Account: "Assets:PM", Amount: usd("-5.00")}, {Account: "Expenses:Repairs", Amount: parser.Amount{Value: "5.00", Currency: "CAD"}}}},
+		{Line: 13, Date: "2025-03-03", Postings: []parser.Posting{
+			{Account: "Assets:PM", Amount: usd("-30.00")},
+			{Account: "Income:Pet-Fee:206-Hoover-Ave", Amount: usd("20.00")},
+			{Account: "Income:Pet-Fee:206-Hoover-Ave", Amount: usd("10.00")},
+		}},
+		{Line: 14, Date: "2025-03-31", Directive: "balance", BalanceAccount: "Liabilities:Old-Loan", BalanceAmount: usd("0.00")},
+		{Line: 15, Date: "2025-02-01", Directive: "note", NoteAccount: "Income:Pet-Fee:206-Hoover-Ave", Narration: "Pet deposit"},
+	}
+	var got []string
+	issues := CheckAccounts(txs, accounts)
+	for _, issue := range issues {
+		got = append(got, issue.Error())
+	}
+	want := []string{
+		"line 11: 2024-12-31 Expenses:Repairs: account opens on 2025-01-01, after the entry",
+		"line 12: 2025-03-02 Expenses:Repairs: currency CAD is not allowed (want USD)",
+		"line 13: 2025-03-03 Income:Pet-Fee:206-Hoover-Ave: account is not opened in the accounts header",
+		"line 14: 2025-03-31 Liabilities:Old-Loan: account was closed on 2023-06-30",
+		"line 15: 2025-02-01 Income:Pet-Fee:206-Hoover-Ave: account is not opened in the accounts header",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckAccounts() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	opens := MissingOpens(issues)
+	wantOpens := []OpenAccount{{Account: "Income:Pet-Fee:206-Hoover-Ave", Opened: "2025-02-01", Currencies: []string{"USD"}}}
+	if !reflect.DeepEqual(opens, wantOpens) {
+		t.Errorf("MissingOpens() = %+v, want %+v", opens, wantOpens)
+	}
+}
+
+func TestWriteOpens(t *testing.T) {
+	opens := []OpenAccount{
+		{Account: "Income:Pet-Fee:206-Hoover-Ave", Opened: "2025-02-01", Currencies: []string{"USD"}},
+		{Account: "Liabilities:Mortgages:SPS", Opened: "2023-10-16"},
+	}
+	tests := []struct {
+		format OutputFormat
+		want   string
+	}{
+		{format: OutputBeancount, want: "2025-02-01 open Income:Pet-Fee:206-Hoover-Ave USD\n2023-10-16 open Liabilities:Mortgages:SPS\n"},
+		{format: OutputLedger, want: "account Income:Pet-Fee:206-Hoover-Ave\naccount Liabilities:Mortgages:SPS\n"},
+		{format: OutputHledger, want: "account Income:Pet-Fee:206-Hoover-Ave\naccount Liabilities:Mortgages:SPS\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteOpens(&b, opens, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("WriteOpens() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
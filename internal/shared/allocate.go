@@ -0,0 +1,274 @@
+// internal/shared/allocate.go
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// Allocation methods.
+const (
+	// AllocateByRatio splits by each share's fixed ratio.
+	AllocateByRatio = "ratio"
+	// AllocateByRent splits by each property's share of the rent income in
+	// the same import.
+	AllocateByRent = "rent"
+)
+
+// Allocation splits postings that cover several properties, like a
+// portfolio management fee, into one posting per property.
+//
+// A posting is split when its account is Account or one of its
+// subaccounts and the transaction matches the Matcher. The split postings
+// go to Into (default Account) followed by ":" and the property.
+type Allocation struct {
+	Matcher
+	Account string `json:"account"`
+	Into    string `json:"into,omitempty"`
+	// By is AllocateByRatio (the default) or AllocateByRent.
+	By string `json:"by,omitempty"`
+	// Shares lists the properties to split across. Ratios are relative,
+	// so 1:1 and 50:50 split alike. Splits by rent use every property
+	// with rent income when Shares is empty, and ignore the ratios.
+	Shares []Share `json:"shares,omitempty"`
+}
+
+// Share is one property's part of an allocation.
+type Share struct {
+	Property string  `json:"property"`
+	Ratio    float64 `json:"ratio,omitempty"`
+}
+
+// allocationFile is the layout of an allocations file.
+type allocationFile struct {
+	Allocations []Allocation `json:"allocations"`
+}
+
+// LoadAllocations reads the allocations in a JSON file of the form
+// {"allocations": [...]}.
+func LoadAllocations(path string) ([]Allocation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var file allocationFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i := range file.Allocations {
+		if err := file.Allocations[i].compile(); err != nil {
+			return nil, fmt.Errorf("%s: allocation %d: %w", path, i+1, err)
+		}
+	}
+	slog.Debug("Loaded allocations", "path", path, "count", len(file.Allocations))
+	return file.Allocations, nil
+}
+
+// compile checks the allocation and compiles its Matcher.
+func (a *Allocation) compile() error {
+	if a.Account == "" {
+		return fmt.Errorf("account is required")
+	}
+	switch a.By {
+	case "", AllocateByRatio:
+		if len(a.Shares) < 2 {
+			return fmt.Errorf("a ratio split needs at least two shares")
+		}
+		for _, share := range a.Shares {
+			if share.Ratio <= 0 || math.IsInf(share.Ratio, 0) {
+				return fmt.Errorf("share %q needs a positive ratio", share.Property)
+			}
+		}
+	case AllocateByRent:
+	default:
+		return fmt.Errorf("unknown method %q (want %s or %s)", a.By, AllocateByRatio, AllocateByRent)
+	}
+	for _, share := range a.Shares {
+		if share.Property == "" {
+			return fmt.Errorf("share without a property")
+		}
+	}
+	return a.Matcher.compile()
+}
+
+// matches reports whether the allocation applies to posting p of tx.
+func (a *Allocation) matches(tx *parser.Transaction, p parser.Posting) bool {
+	if p.Account != a.Account && !strings.HasPrefix(p.Account, a.Account+":") {
+		return false
+	}
+	return a.Matcher.Matches(tx)
+}
+
+// Allocate splits the postings the allocations match, using the first
+// allocation that matches each posting. Each split posting gets its
+// share of the amount in whole cents; the cents left over from rounding
+// go to the shares with the largest remainders, so every transaction
+// still balances exactly. Rent shares are taken from the Income:Rent
+// postings in txs.
+func Allocate(txs []*parser.Transaction, allocations []Allocation) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+	rent, err := rentByProperty(txs)
+	if err != nil {
+		return err
+	}
+	split := 0
+	for _, tx := range txs {
+		var postings []parser.Posting
+		for _, p := range tx.Postings {
+			i := indexAllocation(allocations, tx, p)
+			if i == -1 {
+				postings = append(postings, p)
+				continue
+			}
+			parts, err := allocations[i].split(p, rent)
+			if err != nil {
+				return fmt.Errorf("line %d: allocate %s: %w", tx.Line, p.Account, err)
+			}
+			postings = append(postings, parts...)
+			split++
+		}
+		tx.Postings = postings
+	}
+	slog.Debug("Allocated postings across properties", "postings", split)
+	return nil
+}
+
+// indexAllocation returns the index of the first allocation matching
+// posting p of tx, or -1.
+func indexAllocation(allocations []Allocation, tx *parser.Transaction, p parser.Posting) int {
+	for i := range allocations {
+		if allocations[i].matches(tx, p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// split divides posting p into one posting per share.
+func (a *Allocation) split(p parser.Posting, rent map[string]int64) ([]parser.Posting, error) {
+	properties, weights, err := a.weights(rent)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := cents(p.Amount.Value)
+	if err != nil {
+		return nil, err
+	}
+	into := a.Into
+	if into == "" {
+		into = a.Account
+	}
+	var postings []parser.Posting
+	for i, part := range apportion(amount, weights) {
+		if part == 0 {
+			continue
+		}
+		postings = append(postings, parser.Posting{
+			Account: into + ":" + properties[i],
+			Amount:  parser.Amount{Value: formatCents(part), Currency: p.Amount.Currency},
+		})
+	}
+	return postings, nil
+}
+
+// weights returns the properties to split across and their weights.
+func (a *Allocation) weights(rent map[string]int64) ([]string, []float64, error) {
+	var properties []string
+	var weights []float64
+	if a.By != AllocateByRent {
+		for _, share := range a.Shares {
+			properties = append(properties, share.Property)
+			weights = append(weights, share.Ratio)
+		}
+		return properties, weights, nil
+	}
+
+	if len(a.Shares) == 0 {
+		properties = slices.Sorted(maps.Keys(rent))
+	}
+	for _, share := range a.Shares {
+		properties = append(properties, share.Property)
+	}
+	total := 0.0
+	for _, property := range properties {
+		// Refunds can leave a property with less than no rent.
+		w := float64(max(rent[property], 0))
+		weights = append(weights, w)
+		total += w
+	}
+	if total == 0 {
+		return nil, nil, fmt.Errorf("no rent income to split by")
+	}
+	return properties, weights, nil
+}
+
+// apportion splits amount cents by weights. Each part is rounded toward
+// zero and the cents left over go, one each, to the parts with the largest
+// remainders, earlier parts first on ties.
+func apportion(amount int64, weights []float64) []int64 {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	sign := int64(1)
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+	parts := make([]int64, len(weights))
+	remainders := make([]float64, len(weights))
+	left := amount
+	for i, w := range weights {
+		exact := float64(amount) * w / total
+		parts[i] = int64(math.Floor(exact))
+		remainders[i] = exact - float64(parts[i])
+		left -= parts[i]
+	}
+	for ; left > 0; left-- {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		parts[largest]++
+		remainders[largest] = -1
+	}
+	for i := range parts {
+		parts[i] *= sign
+	}
+	return parts
+}
+
+// rentByProperty sums the rent income in txs by property, in cents.
+func rentByProperty(txs []*parser.Transaction) (map[string]int64, error) {
+	const prefix = "Income:Rent:"
+	rent := map[string]int64{}
+	for _, tx := range txs {
+		for _, p := range tx.Postings {
+			property, ok := strings.CutPrefix(p.Account, prefix)
+			if !ok {
+				continue
+			}
+			amount, err := cents(p.Amount.Value)
+			if err != nil {
+				return nil, fmt.Errorf("posting to %s: %w", p.Account, err)
+			}
+			// Income is negative.
+			rent[property] -= amount
+		}
+	}
+	return rent, nil
+}
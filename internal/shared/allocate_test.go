@@ -0,0 +1,159 @@
+// internal/shared/allocate_test.go
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestAllocate(t *testing.T) {
+	posting := func(account, value string) parser.Posting {
+		return parser.Posting{Account: account, Amount: parser.Amount{Value: value, Currency: "USD"}}
+	}
+	rent := []*parser.Transaction{
+		{Payee: "Tenant", Postings: []parser.Posting{posting("Income:Rent:206-Hoover-Ave", "-1000.00"), posting("Assets:PM", "1000.00")}},
+		{Payee: "Tenant", Postings: []parser.Posting{posting("Income:Rent:2943-Butterfly-Palm", "-2000.00"), posting("Assets:PM", "2000.00")}},
+	}
+	halves := []Share{{Property: "206-Hoover-Ave", Ratio: 1}, {Property: "2943-Butterfly-Palm", Ratio: 1}}
+
+	tests := []struct {
+		name        string
+		allocations []Allocation
+		tx          *parser.Transaction
+		want        []string
+		wantErr     string
+	}{
+		{
+			name:        "odd cent goes to the first share",
+			allocations: []Allocation{{Account: "Expenses:Bank-Fees", Shares: halves}},
+			tx:          &parser.Transaction{Postings: []parser.Posting{posting("Assets:PM", "-25.01"), posting("Expenses:Bank-Fees", "25.01")}},
+			want:        []string{"Assets:PM -25.01", "Expenses:Bank-Fees:206-Hoover-Ave 12.51", "Expenses:Bank-Fees:2943-Butterfly-Palm 12.50"},
+		},
+		{
+			name: "largest remainder",
+			allocations: []Allocation{{Account: "Expenses:Tax-Prep", Shares: []Share{
+				{Property: "A", Ratio: 1}, {Property: "B", Ratio: 1}, {Property: "C", Ratio: 1},
+			}}},
+			tx:   &parser.Transaction{Postings: []parser.Posting{posting("Assets:PM", "-100.00"), posting("Expenses:Tax-Prep", "100.00")}},
+			want: []string{"Assets:PM -100.00", "Expenses:Tax-Prep:A 33.34", "Expenses:Tax-Prep:B 33.33", "Expenses:Tax-Prep:C 33.33"},
+		},
+		{
+			name:        "negative amount",
+			allocations: []Allocation{{Account: "Expenses:Bank-Fees", Shares: []Share{{Property: "A", Ratio: 40}, {Property: "B", Ratio: 60}}}},
+			tx:          &parser.Transaction{Postings: []parser.Posting{posting("Expenses:Bank-Fees", "-0.05"), posting("Assets:PM", "0.05")}},
+			want:        []string{"Expenses:Bank-Fees:A -0.02", "Expenses:Bank-Fees:B -0.03", "Assets:PM 0.05"},
+		},
+		{
+			name:        "into another account",
+			allocations: []Allocation{{Account: "Expenses:Management-Fees", Into: "Expenses:Portfolio-Fees", Shares: halves}},
+			tx:          &parser.Transaction{Postings: []parser.Posting{posting("Assets:PM", "-90.00"), posting("Expenses:Management-Fees:2943-Butterfly-Palm", "90.00")}},
+			want:        []string{"Assets:PM -90.00", "Expenses:Portfolio-Fees:206-Hoover-Ave 45.00", "Expenses:Portfolio-Fees:2943-Butterfly-Palm 45.00"},
+		},
+		{
+			name:        "by rent share",
+			allocations: []Allocation{{Account: "Expenses:Other", By: AllocateByRent}},
+			tx:          &parser.Transaction{Postings: []parser.Posting{posting("Assets:PM", "-100.00"), posting("Expenses:Other", "100.00")}},
+			want:        []string{"Assets:PM -100.00", "Expenses:Other:206-Hoover-Ave 33.33", "Expenses:Other:2943-Butterfly-Palm 66.67"},
+		},
+		{
+			name:        "by rent share of listed properties",
+			allocations: []Allocation{{Account: "Expenses:Other", By: AllocateByRent, Shares: []Share{{Property: "206-Hoover-Ave"}, {Property: "Vacant-Lot"}}}},
+			tx:          &parser.Transaction{Postings: []parser.Posting{posting("Assets:PM", "-100.00"), posting("Expenses:Other", "100.00")}},
+			want:        []string{"Assets:PM -100.00", "Expenses:Other:206-Hoover-Ave 100.00"},
+		},
+		{
+			name:        "payee does not match",
+			allocations: []Allocation{{Matcher: Matcher{Payee: "Bank"}, Account: "Expenses:Other", Shares: halves}},
+			tx:          &parser.Transaction{Payee: "Plumber", Postings: []parser.Posting{posting("Assets:PM", "-10.00"), posting("Expenses:Other", "10.00")}},
+			want:        []string{"Assets:PM -10.00", "Expenses:Other 10.00"},
+		},
+		{
+			name: "first matching allocation wins",
+			allocations: []Allocation{
+				{Matcher: Matcher{Payee: "bank", Narration: "(?i)wire fee"}, Account: "Expenses:Other", Into: "Expenses:Bank-Fees", Shares: halves},
+				{Account: "Expenses:Other", Shares: []Share{{Property: "A", Ratio: 1}, {Property: "B", Ratio: 3}}},
+			},
+			tx:   &parser.Transaction{Payee: "Bank", Narration: "Memo: Wire Fee", Postings: []parser.Posting{posting("Assets:PM", "-10.00"), posting("Expenses:Other", "10.00")}},
+			want: []string{"Assets:PM -10.00", "Expenses:Bank-Fees:206-Hoover-Ave 5.00", "Expenses:Bank-Fees:2943-Butterfly-Palm 5.00"},
+		},
+		{
+			name:        "no rent to split by",
+			allocations: []Allocation{{Account: "Expenses:Other", By: AllocateByRent, Shares: []Share{{Property: "Vacant-Lot"}}}},
+			tx:          &parser.Transaction{Line: 42, Postings: []parser.Posting{posting("Assets:PM", "-10.00"), posting("Expenses:Other", "10.00")}},
+			wantErr:     "line 42: allocate Expenses:Other: no rent income to split by",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.allocations {
+				if err := tt.allocations[i].compile(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			txs := append([]*parser.Transaction{tt.tx}, rent...)
+			err := Allocate(txs, tt.allocations)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Allocate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range tt.tx.Postings {
+				got = append(got, p.Account+" "+p.Amount.Value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("postings = %q, want %q", got, tt.want)
+			}
+			if err := ValidateTransactions([]*parser.Transaction{tt.tx}); err != nil {
+				t.Errorf("allocated transaction does not balance: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadAllocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `{"allocations": [{"payee": "Bank", "account": "Expenses:Other", "shares": [{"property": "A", "ratio": 1}, {"property": "B", "ratio": 2}]}, {"narration": "(?i)tax prep", "account": "Expenses:Other", "by": "rent"}]}`,
+		},
+		{name: "no account", content: `{"allocations": [{"by": "rent"}]}`, wantErr: "allocation 1: account is required"},
+		{name: "one share", content: `{"allocations": [{"account": "Expenses:Other", "shares": [{"property": "A", "ratio": 1}]}]}`, wantErr: "needs at least two shares"},
+		{name: "zero ratio", content: `{"allocations": [{"account": "Expenses:Other", "shares": [{"property": "A", "ratio": 1}, {"property": "B"}]}]}`, wantErr: `share "B" needs a positive ratio`},
+		{name: "unknown method", content: `{"allocations": [{"account": "Expenses:Other", "by": "sqft"}]}`, wantErr: `unknown method "sqft"`},
+		{name: "bad narration", content: `{"allocations": [{"account": "Expenses:Other", "by": "rent", "narration": "("}]}`, wantErr: "narration:"},
+		{name: "unknown field", content: `{"allocations": [{"account": "Expenses:Other", "by": "rent", "ratio": 1}]}`, wantErr: `unknown field "ratio"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "allocations.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			allocations, err := LoadAllocations(path)
+			if tt.wantErr == "" {
+				if err != nil || len(allocations) != 2 {
+					t.Errorf("LoadAllocations() = %d allocations, %v; want 2, nil", len(allocations), err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadAllocations() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
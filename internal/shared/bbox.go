@@ -0,0 +1,154 @@
+// internal/shared/bbox.go
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/layout"
+)
+
+// ExtractLayout extracts positioned words from the PDF, grouped into pages
+// and lines, using the configured extractor. pdftotext is run with
+// -bbox-layout; the native extractor reads glyph positions directly. With
+// opts.OCR, pages without a text layer are recognized with tesseract.
+func ExtractLayout(pdfPath string, opts ExtractOptions) (*layout.Document, error) {
+	return ExtractLayoutContext(context.Background(), pdfPath, opts)
+}
+
+// ExtractLayoutContext is ExtractLayout that gives up once ctx is done.
+func ExtractLayoutContext(ctx context.Context, pdfPath string, opts ExtractOptions) (*layout.Document, error) {
+	extractor := resolveExtractor(opts.Extractor)
+	slog.Debug("Extracting layout from PDF", "path", pdfPath, "extractor", extractor)
+	doc, err := extractLayout(ctx, pdfPath, opts.Passwords, extractor)
+	if err != nil {
+		slog.Error("Failed to extract layout", "extractor", extractor, "error", err)
+		return nil, err
+	}
+	if opts.OCR {
+		for i, page := range doc.Pages {
+			if hasWords(page) {
+				continue
+			}
+			recognized, err := ocrPage(ctx, pdfPath, page.Number, opts.Passwords)
+			if err != nil {
+				slog.Error("Failed to OCR page", "page", page.Number, "error", err)
+				return nil, err
+			}
+			doc.Pages[i] = recognized
+		}
+	}
+	slog.Debug("Extracted layout", "pages", len(doc.Pages))
+	return doc, nil
+}
+
+// extractLayout reads the positioned words of every page with a concrete
+// extractor. Text extraction renders the same words, so parsers find table
+// columns at the words' positions whichever extractor is used.
+func extractLayout(ctx context.Context, pdfPath string, passwords Passwords, extractor Extractor) (*layout.Document, error) {
+	switch extractor {
+	case ExtractorPdftotext:
+		return extractPdftotextLayout(ctx, pdfPath, passwords)
+	case ExtractorNative:
+		return extractNativeLayout(ctx, pdfPath, passwords)
+	default:
+		return nil, fmt.Errorf("unsupported extractor %v", extractor)
+	}
+}
+
+// hasWords reports whether the page has a text layer.
+func hasWords(page layout.Page) bool {
+	for _, line := range page.Lines {
+		if len(line.Words) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// extractPdftotextLayout runs pdftotext -bbox-layout on the PDF.
+func extractPdftotextLayout(ctx context.Context, pdfPath string, passwords Passwords) (*layout.Document, error) {
+	output, err := runPdftotext(ctx, pdfPath, passwords, "-bbox-layout")
+	if err != nil {
+		return nil, err
+	}
+	return parseBBoxLayout(bytes.NewReader(output))
+}
+
+// parseBBoxLayout reads the XHTML written by `pdftotext -bbox-layout`. Only
+// page and word elements are used; words are regrouped into visual lines
+// because pdftotext's own blocks split table rows into columns.
+func parseBBoxLayout(r io.Reader) (*layout.Document, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	doc := &layout.Document{}
+	var page *layout.Page
+	var words []layout.Word
+	flush := func() {
+		if page != nil {
+			page.Lines = layout.GroupLines(words)
+			doc.Pages = append(doc.Pages, *page)
+		}
+		page, words = nil, nil
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse bbox layout: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "page":
+			flush()
+			page = &layout.Page{
+				Number: len(doc.Pages) + 1,
+				Width:  floatAttr(start, "width"),
+				Height: floatAttr(start, "height"),
+			}
+		case "word":
+			var text string
+			if err := decoder.DecodeElement(&text, &start); err != nil {
+				return nil, fmt.Errorf("parse bbox layout: %w", err)
+			}
+			if page == nil || strings.TrimSpace(text) == "" {
+				continue
+			}
+			words = append(words, layout.Word{
+				Text: strings.TrimSpace(text),
+				XMin: floatAttr(start, "xMin"),
+				YMin: floatAttr(start, "yMin"),
+				XMax: floatAttr(start, "xMax"),
+				YMax: floatAttr(start, "yMax"),
+			})
+		}
+	}
+	flush()
+	return doc, nil
+}
+
+// floatAttr returns the named attribute as a number, or 0 when absent or malformed.
+func floatAttr(start xml.StartElement, name string) float64 {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			v, _ := strconv.ParseFloat(attr.Value, 64)
+			return v
+		}
+	}
+	return 0
+}
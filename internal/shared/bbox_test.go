@@ -0,0 +1,172 @@
+// internal/shared/bbox_test.go
+package shared
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/layout"
+)
+
+const sampleBBoxLayout = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<title></title>
+<meta name="Producer" content="pdftotext"/>
+</head>
+<body>
+<doc>
+  <page width="612.000000" height="792.000000">
+    <flow>
+      <block xMin="36.0" yMin="38.0" xMax="60.0" yMax="60.0">
+        <line xMin="36.0" yMin="38.0" xMax="60.0" yMax="48.0">
+          <word xMin="36.000000" yMin="38.000000" xMax="60.000000" yMax="48.000000">Date</word>
+        </line>
+        <line xMin="36.0" yMin="50.0" xMax="66.0" yMax="60.0">
+          <word xMin="36.000000" yMin="50.000000" xMax="66.000000" yMax="60.000000">11/05</word>
+        </line>
+      </block>
+      <block xMin="120.0" yMin="38.0" xMax="156.0" yMax="60.0">
+        <line xMin="120.0" yMin="38.0" xMax="156.0" yMax="48.0">
+          <word xMin="120.000000" yMin="38.000000" xMax="156.000000" yMax="48.000000">Amount</word>
+        </line>
+        <line xMin="120.0" yMin="50.0" xMax="156.0" yMax="60.0">
+          <word xMin="120.000000" yMin="50.000000" xMax="156.000000" yMax="60.000000">R&amp;M</word>
+        </line>
+      </block>
+    </flow>
+  </page>
+  <page width="612.000000" height="792.000000">
+    <flow>
+      <block xMin="36.0" yMin="38.0" xMax="60.0" yMax="48.0">
+        <line xMin="36.0" yMin="38.0" xMax="60.0" yMax="48.0">
+          <word xMin="36.000000" yMin="38.000000" xMax="60.000000" yMax="48.000000">Total</word>
+        </line>
+      </block>
+    </flow>
+  </page>
+</doc>
+</body>
+</html>
+`
+
+func TestParseBBoxLayout(t *testing.T) {
+	doc, err := parseBBoxLayout(strings.NewReader(sampleBBoxLayout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(doc.Pages))
+	}
+
+	var got []string
+	for _, line := range doc.Pages[0].Lines {
+		got = append(got, line.Text())
+	}
+	// Blocks are columns in pdftotext output; rows must be regrouped.
+	want := []string{"Date Amount", "11/05 R&M"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("page 1 lines = %q, want %q", got, want)
+	}
+
+	page := doc.Pages[1]
+	if page.Number != 2 || page.Width != 612 || page.Height != 792 {
+		t.Errorf("page 2 = number %d, %gx%g", page.Number, page.Width, page.Height)
+	}
+	if w := page.Lines[0].Words[0]; w.XMin != 36 || w.YMax != 48 {
+		t.Errorf("page 2 word box = %+v", w)
+	}
+}
+
+func TestExtractLayoutNative(t *testing.T) {
+	pdfPath := filepath.Join(t.TempDir(), "layout.pdf")
+	writeTestPDF(t, pdfPath, "Date        Amount\n11/05/2025  $1,200.00\fTotal       $1,200.00")
+
+	doc, err := ExtractLayout(pdfPath, ExtractOptions{Extractor: ExtractorNative})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(doc.Pages))
+	}
+
+	row := doc.Pages[0].Lines[1]
+	if row.Text() != "11/05/2025 $1,200.00" {
+		t.Fatalf("row text = %q", row.Text())
+	}
+	// writeTestPDF draws 6pt cells from a 36pt margin and 12pt lines.
+	amount := row.Words[1]
+	if amount.XMin != 36+12*6 || amount.XMax != 36+21*6 {
+		t.Errorf("amount x = [%g, %g], want [108, 162]", amount.XMin, amount.XMax)
+	}
+	if amount.YMax != 36+2*12 {
+		t.Errorf("amount baseline = %g, want 60", amount.YMax)
+	}
+	if got := doc.Pages[1].Lines[0].Text(); got != "Total $1,200.00" {
+		t.Errorf("page 2 text = %q", got)
+	}
+}
+
+func TestExtractLayoutPdftotext(t *testing.T) {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		t.Skip("pdftotext not available")
+	}
+	pdfPath := filepath.Join(t.TempDir(), "layout.pdf")
+	writeTestPDF(t, pdfPath, "Date        Amount\n11/05/2025  $1,200.00")
+
+	native, err := ExtractLayout(pdfPath, ExtractOptions{Extractor: ExtractorNative})
+	if err != nil {
+		t.Fatal(err)
+	}
+	poppler, err := ExtractLayout(pdfPath, ExtractOptions{Extractor: ExtractorPdftotext})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := poppler.Text(), native.Text(); normalizeLayout(got) != normalizeLayout(want) {
+		t.Errorf("pdftotext layout = %q, native = %q", got, want)
+	}
+}
+
+// TestExtractTextPdftotextColumns checks that text extracted with pdftotext
+// places words by their boxes, so the amount columns parsers locate by
+// x-position keep Cash In and Cash Out apart.
+func TestExtractTextPdftotextColumns(t *testing.T) {
+	fakeTool(t, "pdftotext", `cat <<'EOF'
+<doc><page width="612" height="792">
+<word xMin="36" yMin="38" xMax="58" yMax="48">Date</word>
+<word xMin="120" yMin="38" xMax="146" yMax="48">Memo</word>
+<word xMin="300" yMin="38" xMax="322" yMax="48">Cash</word>
+<word xMin="325" yMin="38" xMax="333" yMax="48">In</word>
+<word xMin="400" yMin="38" xMax="422" yMax="48">Cash</word>
+<word xMin="425" yMin="38" xMax="440" yMax="48">Out</word>
+<word xMin="36" yMin="50" xMax="86" yMax="60">11/05/2025</word>
+<word xMin="120" yMin="50" xMax="140" yMax="60">Rent</word>
+<word xMin="300" yMin="50" xMax="333" yMax="60">1,200.00</word>
+<word xMin="36" yMin="62" xMax="86" yMax="72">11/06/2025</word>
+<word xMin="120" yMin="62" xMax="160" yMax="72">Plumbing</word>
+<word xMin="410" yMin="62" xMax="440" yMax="72">350.00</word>
+</page></doc>
+EOF`)
+
+	text, err := ExtractTextWithOptions("statement.pdf", ExtractOptions{Extractor: ExtractorPdftotext})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := layout.TextLines(text)
+	columns, err := layout.Columns(lines[0], "Date", "Memo", "Cash In", "Cash Out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"11/05/2025", "Rent", "1,200.00", ""},
+		{"11/06/2025", "Plumbing", "", "350.00"},
+	}
+	for i, row := range want {
+		if got := lines[i+1].Cells(columns); !reflect.DeepEqual(got, row) {
+			t.Errorf("row %d cells = %q, want %q\n%s", i+1, got, row, text)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// internal/shared/cache.go
+package shared
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cacheFormatVersion changes whenever the cache entry layout changes.
+const cacheFormatVersion = 1
+
+// TextCache stores extracted text on disk so re-running lgo over the same
+// statements skips extraction. Entries are keyed by the SHA-256 of the PDF
+// plus everything that affects the output: the extractor and its version
+// and the OCR settings. Entries may hold text from password-protected
+// PDFs, so files are created readable only by their owner.
+type TextCache struct {
+	Dir string
+}
+
+// cacheEntry is the JSON stored for one extraction.
+type cacheEntry struct {
+	Version    int         `json:"version"`
+	Source     string      `json:"source"`
+	Key        string      `json:"key"`
+	Extraction *Extraction `json:"extraction"`
+}
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/lgo/text, usually ~/.cache/lgo/text.
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "lgo", "text")
+}
+
+// Key returns the cache key for extracting pdfPath with opts.
+func (c *TextCache) Key(ctx context.Context, pdfPath string, opts ExtractOptions) (string, error) {
+	file, err := os.Open(pdfPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	content := sha256.New()
+	if _, err := io.Copy(content, file); err != nil {
+		return "", err
+	}
+
+	extractor := resolveExtractor(opts.Extractor)
+	h := sha256.New()
+	fmt.Fprintf(h, "lgo-text-cache %d\n", cacheFormatVersion)
+	fmt.Fprintf(h, "pdf %x\n", content.Sum(nil))
+	fmt.Fprintf(h, "extractor %s %s\n", extractor, extractorVersion(ctx, extractor))
+	fmt.Fprintf(h, "ocr %t %d\n", opts.OCR, OCRDPI)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractorVersion identifies the extractor build, so upgrading poppler or
+// changing the native layout invalidates old entries.
+func extractorVersion(ctx context.Context, extractor Extractor) string {
+	switch extractor {
+	case ExtractorNative:
+		return fmt.Sprintf("v%d", NativeExtractorVersion)
+	case ExtractorPdftotext:
+		// pdftotext -v prints its version to stderr; older builds exit non-zero.
+		output, _ := exec.CommandContext(ctx, "pdftotext", "-v").CombinedOutput()
+		first, _, _ := strings.Cut(string(output), "\n")
+		return "bbox-layout " + strings.TrimSpace(first)
+	default:
+		return ""
+	}
+}
+
+func (c *TextCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// Get returns the cached extraction for key, or nil on a miss. Unreadable or
+// outdated entries count as misses.
+func (c *TextCache) Get(key string) *Extraction {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("Failed to read text cache entry", "key", key, "error", err)
+		}
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Version != cacheFormatVersion || entry.Key != key || entry.Extraction == nil {
+		slog.Warn("Ignoring invalid text cache entry", "key", key, "error", err)
+		return nil
+	}
+	// Refresh the modification time so prune keeps entries still in use.
+	now := time.Now()
+	os.Chtimes(c.path(key), now, now)
+	return entry.Extraction
+}
+
+// Put stores an extraction under key, replacing any existing entry.
+func (c *TextCache) Put(key, source string, extraction *Extraction) error {
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cacheEntry{Version: cacheFormatVersion, Source: source, Key: key, Extraction: extraction})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, "."+key+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Prune removes entries not used for longer than olderThan, or every entry
+// when olderThan is zero, and returns how many were removed.
+func (c *TextCache) Prune(olderThan time.Duration) (int, error) {
+	entries, err := os.ReadDir(c.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return removed, err
+		}
+		if olderThan > 0 && info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.Dir, entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	slog.Debug("Pruned text cache", "dir", c.Dir, "removed", removed)
+	return removed, nil
+}
+
+// cachedExtract wraps run with the cache in opts. Cache failures are
+// logged and never fail the extraction.
+func cachedExtract(ctx context.Context, pdfPath string, opts ExtractOptions, run func() (*Extraction, error)) (*Extraction, error) {
+	cache := opts.Cache
+	if cache == nil {
+		return run()
+	}
+	key, err := cache.Key(ctx, pdfPath, opts)
+	if err != nil {
+		slog.Warn("Skipping text cache", "path", pdfPath, "error", err)
+		return run()
+	}
+	if extraction := cache.Get(key); extraction != nil {
+		slog.Debug("Text cache hit", "path", pdfPath, "key", key)
+		return extraction, nil
+	}
+	extraction, err := run()
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Put(key, pdfPath, extraction); err != nil {
+		slog.Warn("Failed to write text cache entry", "path", pdfPath, "error", err)
+	} else {
+		slog.Debug("Cached extracted text", "path", pdfPath, "key", key)
+	}
+	return extraction, nil
+}
@@ -0,0 +1,145 @@
+// internal/shared/cache_test.go
+package shared
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingPdftotext installs a fake pdftotext reporting the given version and
+// returns a function counting its extraction runs.
+func countingPdftotext(t *testing.T, version string) func() int {
+	t.Helper()
+	counter := filepath.Join(t.TempDir(), "runs")
+	fakeTool(t, "pdftotext", `if [ "$1" = -v ]; then echo "pdftotext version `+version+`" >&2; exit 0; fi
+echo run >> "`+counter+`"
+printf '<doc><page width="612" height="792"><word xMin="36" yMin="38" xMax="66" yMax="48">Owner</word><word xMin="72" yMin="38" xMax="126" yMax="48">Statement</word></page></doc>'`)
+	return func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "run")
+	}
+}
+
+func TestExtractContextCache(t *testing.T) {
+	runs := countingPdftotext(t, "24.02.0")
+	pdfPath := filepath.Join(t.TempDir(), "statement.pdf")
+	if err := os.WriteFile(pdfPath, []byte("%PDF-1.4 first"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache := &TextCache{Dir: filepath.Join(t.TempDir(), "cache")}
+	opts := ExtractOptions{Extractor: ExtractorPdftotext, Cache: cache}
+
+	extract := func(opts ExtractOptions) *Extraction {
+		t.Helper()
+		extraction, err := ExtractContext(context.Background(), pdfPath, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return extraction
+	}
+
+	loads := 0
+	opts.PasswordLoader = func() (Passwords, error) {
+		loads++
+		return Passwords{}, nil
+	}
+	first := extract(opts)
+	second := extract(opts)
+	if loads != 1 {
+		t.Errorf("passwords loaded %d times for one extraction and one cache hit, want 1", loads)
+	}
+	if runs() != 1 {
+		t.Errorf("pdftotext ran %d times for two identical extractions, want 1", runs())
+	}
+	if second.Text != first.Text || len(second.Pages) != len(first.Pages) {
+		t.Errorf("cached extraction = %+v, want %+v", second, first)
+	}
+
+	extract(ExtractOptions{Extractor: ExtractorPdftotext})
+	if runs() != 2 {
+		t.Errorf("extraction without a cache did not run pdftotext")
+	}
+
+	opts.OCR = true
+	extract(opts)
+	if runs() != 3 {
+		t.Errorf("changing OCR options reused the cache")
+	}
+	opts.OCR = false
+
+	if err := os.WriteFile(pdfPath, []byte("%PDF-1.4 second"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	extract(opts)
+	if runs() != 4 {
+		t.Errorf("changing the PDF reused the cache")
+	}
+
+	countingPdftotext(t, "25.01.0")
+	extract(opts)
+	if entries, _ := filepath.Glob(filepath.Join(cache.Dir, "*.json")); len(entries) != 4 {
+		t.Errorf("cache has %d entries after a pdftotext upgrade, want 4", len(entries))
+	}
+}
+
+func TestTextCacheEntries(t *testing.T) {
+	cache := &TextCache{Dir: filepath.Join(t.TempDir(), "cache")}
+	extraction := &Extraction{Text: "Owner Statement", Pages: []PageText{{Number: 1, StartLine: 1, EndLine: 1, OCR: true}}}
+	if err := cache.Put("abc", "statement.pdf", extraction); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(cache.Dir, "abc.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("cache entry mode = %v, want 0600", perm)
+	}
+	if got := cache.Get("abc"); got == nil || got.Text != extraction.Text || !got.Pages[0].OCR {
+		t.Errorf("Get() = %+v, want %+v", got, extraction)
+	}
+
+	if err := os.WriteFile(filepath.Join(cache.Dir, "bad.json"), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.Get("bad"); got != nil {
+		t.Errorf("Get() of a corrupt entry = %+v, want miss", got)
+	}
+	if got := cache.Get("missing"); got != nil {
+		t.Errorf("Get() of a missing entry = %+v, want miss", got)
+	}
+}
+
+func TestTextCachePrune(t *testing.T) {
+	cache := &TextCache{Dir: t.TempDir()}
+	for _, key := range []string{"old", "new"} {
+		if err := cache.Put(key, key+".pdf", &Extraction{Text: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(cache.Dir, "old.json"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := cache.Prune(24 * time.Hour)
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune(24h) = %d, %v, want 1 removed", removed, err)
+	}
+	if cache.Get("old") != nil || cache.Get("new") == nil {
+		t.Errorf("Prune(24h) removed the wrong entry")
+	}
+
+	removed, err = cache.Prune(0)
+	if err != nil || removed != 1 {
+		t.Errorf("Prune(0) = %d, %v, want 1 removed", removed, err)
+	}
+	if removed, err := (&TextCache{Dir: filepath.Join(cache.Dir, "missing")}).Prune(0); err != nil || removed != 0 {
+		t.Errorf("Prune() of a missing directory = %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,152 @@
+// internal/shared/classify.go
+package shared
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// ConfidenceKey is the metadata key holding the classifier's confidence in
+// a predicted account, from 0 to 1.
+const ConfidenceKey = "confidence"
+
+// Classifier is a naive Bayes model of the accounts past transactions
+// booked amounts to, given their description words and the size of the
+// amount.
+type Classifier struct {
+	// postings counts the training postings per account.
+	postings map[string]int
+	// tokens counts each token per account, and tokenTotals all tokens per
+	// account.
+	tokens      map[string]map[string]int
+	tokenTotals map[string]int
+	vocabulary  map[string]bool
+	total       int
+}
+
+// TrainClassifier trains a classifier on the postings of the history's
+// transactions. Postings to the suspense account are left out.
+func TrainClassifier(history *History) *Classifier {
+	c := &Classifier{
+		postings:    map[string]int{},
+		tokens:      map[string]map[string]int{},
+		tokenTotals: map[string]int{},
+		vocabulary:  map[string]bool{},
+	}
+	for _, entry := range history.Entries {
+		words := splitWords(entry.Payee + " " + entry.Narration)
+		for _, p := range entry.Postings {
+			if p.Account == parser.SuspenseAccount {
+				continue
+			}
+			if c.tokens[p.Account] == nil {
+				c.tokens[p.Account] = map[string]int{}
+			}
+			for _, token := range features(words, p.Amount.Value) {
+				c.tokens[p.Account][token]++
+				c.tokenTotals[p.Account]++
+				c.vocabulary[token] = true
+			}
+			c.postings[p.Account]++
+			c.total++
+		}
+	}
+	slog.Debug("Trained classifier", "postings", c.total, "accounts", len(c.postings))
+	return c
+}
+
+// Predict returns the most probable account for tx's suspense postings and
+// the model's confidence in it, or "" when tx has no suspense posting or
+// the model has no other account to offer. The accounts tx already posts
+// to are not candidates.
+func (c *Classifier) Predict(tx *parser.Transaction) (string, float64) {
+	value := ""
+	skip := map[string]bool{}
+	for _, p := range tx.Postings {
+		if p.Account == parser.SuspenseAccount {
+			value = p.Amount.Value
+		} else {
+			skip[p.Account] = true
+		}
+	}
+	if value == "" {
+		return "", 0
+	}
+	tokens := features(splitWords(tx.Payee+" "+tx.Narration), value)
+
+	var accounts []string
+	for account := range c.postings {
+		if !skip[account] {
+			accounts = append(accounts, account)
+		}
+	}
+	if len(accounts) == 0 {
+		return "", 0
+	}
+	slices.Sort(accounts)
+
+	// Log probabilities with add-one smoothing, normalized into a posterior.
+	scores := make([]float64, len(accounts))
+	best := 0
+	for i, account := range accounts {
+		score := math.Log(float64(c.postings[account]) / float64(c.total))
+		for _, token := range tokens {
+			count := c.tokens[account][token]
+			score += math.Log(float64(count+1) / float64(c.tokenTotals[account]+len(c.vocabulary)+1))
+		}
+		scores[i] = score
+		if score > scores[best] {
+			best = i
+		}
+	}
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+	return accounts[best], 1 / sum
+}
+
+// features returns the tokens describing a posting: the description words
+// and a bucket for the amount's sign and order of magnitude.
+func features(words []string, value string) []string {
+	tokens := append([]string(nil), words...)
+	if amount, err := strconv.ParseFloat(value, 64); err == nil && amount != 0 {
+		sign := "+"
+		if amount < 0 {
+			sign = "-"
+		}
+		magnitude := int(math.Floor(math.Log10(math.Abs(amount))))
+		tokens = append(tokens, fmt.Sprintf("amount:%s%d", sign, magnitude))
+	}
+	return tokens
+}
+
+// PredictAccounts books each uncategorized transaction to the account c
+// predicts when its confidence is at least minConfidence, recording the
+// confidence under ConfidenceKey. It returns how many were booked.
+func PredictAccounts(txs []*parser.Transaction, c *Classifier, minConfidence float64) int {
+	booked := 0
+	for _, tx := range txs {
+		if !Uncategorized(tx) {
+			continue
+		}
+		account, confidence := c.Predict(tx)
+		if account == "" || confidence < minConfidence {
+			slog.Debug("No confident prediction", "line", tx.Line, "account", account, "confidence", confidence)
+			continue
+		}
+		Categorize(tx, account)
+		if tx.Links == nil {
+			tx.Links = map[string]string{}
+		}
+		tx.Links[ConfidenceKey] = strconv.FormatFloat(confidence, 'f', 2, 64)
+		booked++
+	}
+	slog.Debug("Predicted accounts", "categorized", booked)
+	return booked
+}
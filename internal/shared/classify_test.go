@@ -0,0 +1,72 @@
+// internal/shared/classify_test.go
+package shared
+
+import (
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/normalize"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestPredictAccounts(t *testing.T) {
+	usd := func(value string) parser.Amount { return parser.Amount{Value: value, Currency: "USD"} }
+	entry := func(payee, narration, account, value string) HistoryEntry {
+		return HistoryEntry{Payee: payee, Narration: narration, Postings: []parser.Posting{
+			{Account: account, Amount: usd(value)},
+			{Account: "Assets:PM", Amount: usd(normalize.Negate(value))},
+		}}
+	}
+	classifier := TrainClassifier(&History{Entries: []HistoryEntry{
+		entry("Green Yards", "Memo: Mowing", "Expenses:Landscaping", "40.00"),
+		entry("Green Yards", "Memo: Hedge trimming", "Expenses:Landscaping", "65.00"),
+		entry("Pest Control", "Memo: Quarterly service", "Expenses:Pest-Control", "95.00"),
+		entry("Pest Control", "Memo: Follow-up service", "Expenses:Pest-Control", "95.00"),
+		entry("Tenant", "Memo: Rent", "Income:Rent", "-1600.00"),
+		{Payee: "Unknown", Postings: []parser.Posting{{Account: parser.SuspenseAccount, Amount: usd("5.00")}, {Account: "Assets:PM"}}},
+	}})
+	unmatched := func(payee, narration, value string) *parser.Transaction {
+		tx := &parser.Transaction{Payee: payee, Narration: narration, Postings: []parser.Posting{
+			{Account: "Assets:PM", Amount: usd(normalize.Negate(value))},
+			{Account: parser.SuspenseAccount, Amount: usd(value)},
+		}}
+		tx.MarkForReview(parser.UnmatchedReason)
+		return tx
+	}
+
+	tests := []struct {
+		name           string
+		tx             *parser.Transaction
+		minConfidence  float64
+		want           string
+		wantConfidence string
+	}{
+		{name: "payee words", tx: unmatched("Green Yards", "Memo: Leaf cleanup", "55.00"), minConfidence: 0.6, want: "Expenses:Landscaping", wantConfidence: "0.86"},
+		{name: "narration words", tx: unmatched("Acme", "Memo: Pest service", "95.00"), minConfidence: 0.6, want: "Expenses:Pest-Control", wantConfidence: "0.84"},
+		{name: "amount sign", tx: unmatched("Acme", "Memo: Deposit", "-1500.00"), minConfidence: 0.5, want: "Income:Rent", wantConfidence: "0.53"},
+		{name: "below minimum confidence", tx: unmatched("Acme", "Memo: Roof", "300.00"), minConfidence: 0.6, want: parser.SuspenseAccount},
+		{
+			name:          "already categorized",
+			tx:            &parser.Transaction{Payee: "Green Yards", Postings: []parser.Posting{{Account: "Assets:PM", Amount: usd("-40.00")}, {Account: "Expenses:Other", Amount: usd("40.00")}}},
+			minConfidence: 0,
+			want:          "Expenses:Other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			PredictAccounts([]*parser.Transaction{tt.tx}, classifier, tt.minConfidence)
+			if got := tt.tx.Postings[1].Account; got != tt.want {
+				t.Errorf("account = %q, want %q", got, tt.want)
+			}
+			if got := tt.tx.Links[ConfidenceKey]; got != tt.wantConfidence {
+				t.Errorf("confidence = %q, want %q", got, tt.wantConfidence)
+			}
+			if categorized := tt.wantConfidence != ""; categorized && tt.tx.Flag != "" {
+				t.Errorf("flag = %q after prediction", tt.tx.Flag)
+			}
+		})
+	}
+
+	if account, _ := TrainClassifier(&History{}).Predict(unmatched("Green Yards", "Memo: Mowing", "40.00")); account != "" {
+		t.Errorf("untrained Predict() = %q, want none", account)
+	}
+}
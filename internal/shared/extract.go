@@ -2,21 +2,290 @@
 package shared
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
 	"os/exec"
 	"strings"
+	"unicode"
 )
 
-// ExtractText runs pdftotext on the PDF and returns the extracted text.
+// Extractor selects how text is pulled out of a PDF.
+type Extractor int
+
+const (
+	// ExtractorAuto uses pdftotext when it is on PATH and the native extractor otherwise.
+	ExtractorAuto Extractor = iota
+	// ExtractorPdftotext shells out to poppler's pdftotext -bbox-layout.
+	ExtractorPdftotext
+	// ExtractorNative uses the built-in pure-Go extractor.
+	ExtractorNative
+)
+
+// String returns the flag value for the extractor.
+func (e Extractor) String() string {
+	switch e {
+	case ExtractorAuto:
+		return "auto"
+	case ExtractorPdftotext:
+		return "pdftotext"
+	case ExtractorNative:
+		return "native"
+	default:
+		return fmt.Sprintf("Extractor(%d)", int(e))
+	}
+}
+
+// ParseExtractor converts a flag value into an Extractor.
+func ParseExtractor(value string) (Extractor, error) {
+	switch value {
+	case "", "auto":
+		return ExtractorAuto, nil
+	case "pdftotext":
+		return ExtractorPdftotext, nil
+	case "native":
+		return ExtractorNative, nil
+	default:
+		return 0, fmt.Errorf("unknown extractor %q (want pdftotext, native or auto)", value)
+	}
+}
+
+// ErrPdftotextNotFound is returned when pdftotext is requested but not on PATH.
+var ErrPdftotextNotFound = errors.New("pdftotext not found on PATH (install poppler-utils or use --extractor=native)")
+
+// ExtractOptions configures text extraction.
+type ExtractOptions struct {
+	// Extractor selects the backend; the zero value is ExtractorAuto.
+	Extractor Extractor
+	// Passwords open encrypted PDFs; see LoadPasswords.
+	Passwords Passwords
+	// PasswordLoader, when set, supplies Passwords once a PDF is actually
+	// extracted rather than read from the cache, so runs that never open a
+	// PDF never read secrets files.
+	PasswordLoader func() (Passwords, error)
+	// OCR recognizes pages without a text layer with tesseract.
+	OCR bool
+	// Cache, when set, stores and reuses extracted text.
+	Cache *TextCache
+}
+
+// ExtractText extracts layout text from the PDF with the default options.
 func ExtractText(pdfPath string) (string, error) {
-	slog.Debug("Extracting text from PDF", "path", pdfPath)
-	cmd := exec.Command("pdftotext", "-layout", pdfPath, "-")
+	return ExtractTextWithOptions(pdfPath, ExtractOptions{})
+}
+
+// ExtractTextWithOptions extracts layout text from the PDF using the configured extractor.
+func ExtractTextWithOptions(pdfPath string, opts ExtractOptions) (string, error) {
+	return ExtractTextContext(context.Background(), pdfPath, opts)
+}
+
+// ExtractTextContext is ExtractTextWithOptions that gives up once ctx is
+// done: pdftotext is killed, and the native extractor stops between pages.
+func ExtractTextContext(ctx context.Context, pdfPath string, opts ExtractOptions) (string, error) {
+	extraction, err := ExtractContext(ctx, pdfPath, opts)
+	if err != nil {
+		return "", err
+	}
+	return extraction.Text, nil
+}
+
+// Extraction is extracted statement text together with where each page's
+// lines ended up.
+type Extraction struct {
+	Text  string     `json:"text"`
+	Pages []PageText `json:"pages"`
+}
+
+// PageText locates one PDF page within Extraction.Text.
+type PageText struct {
+	// Number is the 1-based page number.
+	Number int `json:"number"`
+	// StartLine and EndLine are the page's 1-based, inclusive line range.
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+	// OCR reports that the page had no text layer and was recognized by OCR.
+	OCR bool `json:"ocr,omitempty"`
+}
+
+// PageAt returns the page containing the 1-based text line, or nil.
+func (e *Extraction) PageAt(line int) *PageText {
+	for i := range e.Pages {
+		if p := &e.Pages[i]; line >= p.StartLine && line <= p.EndLine {
+			return p
+		}
+	}
+	return nil
+}
+
+// ExtractContext extracts layout text and per-page provenance from the PDF.
+// Pages without a text layer are recognized with OCR when opts.OCR is set
+// and reported with a warning otherwise; a document with no text at all is
+// an ErrNoTextLayer error rather than an empty statement. Results are
+// reused from opts.Cache when set.
+func ExtractContext(ctx context.Context, pdfPath string, opts ExtractOptions) (*Extraction, error) {
+	return cachedExtract(ctx, pdfPath, opts, func() (*Extraction, error) {
+		return extract(ctx, pdfPath, opts)
+	})
+}
+
+// extract implements ExtractContext without the cache.
+func extract(ctx context.Context, pdfPath string, opts ExtractOptions) (*Extraction, error) {
+	if opts.PasswordLoader != nil {
+		passwords, err := opts.PasswordLoader()
+		if err != nil {
+			return nil, fmt.Errorf("load PDF passwords: %w", err)
+		}
+		opts.Passwords = passwords
+	}
+	extractor := resolveExtractor(opts.Extractor)
+	slog.Debug("Extracting text from PDF", "path", pdfPath, "extractor", extractor)
+
+	doc, err := extractLayout(ctx, pdfPath, opts.Passwords, extractor)
+	if err != nil {
+		slog.Error("Failed to extract text", "extractor", extractor, "error", err)
+		return nil, err
+	}
+
+	pages := make([]string, len(doc.Pages))
+	for i, page := range doc.Pages {
+		pages[i] = page.Text()
+	}
+	ocr := make([]bool, len(pages))
+	var missing []int
+	for i, page := range pages {
+		if strings.TrimSpace(page) == "" {
+			missing = append(missing, i+1)
+		}
+	}
+	if len(missing) > 0 {
+		if !opts.OCR {
+			if len(missing) == len(pages) {
+				return nil, fmt.Errorf("%s: %w", pdfPath, ErrNoTextLayer)
+			}
+			slog.Warn("Pages have no text layer and were skipped; rerun with --ocr to recognize them", "pages", missing)
+		} else {
+			for _, number := range missing {
+				page, err := ocrPage(ctx, pdfPath, number, opts.Passwords)
+				if err != nil {
+					slog.Error("Failed to OCR page", "page", number, "error", err)
+					return nil, err
+				}
+				pages[number-1] = page.Text()
+				ocr[number-1] = true
+			}
+			slog.Info("Recognized pages without a text layer using OCR", "pages", missing)
+		}
+	}
+
+	extraction := newExtraction(pages, ocr)
+	if extraction.Text == "" {
+		return nil, fmt.Errorf("%s: %w", pdfPath, ErrNoTextLayer)
+	}
+	slog.Debug("Extracted text", "length", len(extraction.Text), "pages", len(pages))
+	return extraction, nil
+}
+
+// newExtraction joins pages with form feeds, trims the result like
+// ExtractText always has and records each page's line range in it.
+func newExtraction(pages []string, ocr []bool) *Extraction {
+	joined := strings.Join(pages, "\f")
+	trimmed := strings.TrimLeftFunc(joined, unicode.IsSpace)
+	skipped := strings.Count(joined[:len(joined)-len(trimmed)], "\n")
+
+	extraction := &Extraction{Text: strings.TrimSpace(joined)}
+	line := 1 - skipped
+	for i, page := range pages {
+		// The next page starts on the line after this page's last newline.
+		next := line + strings.Count(page, "\n")
+		end := next
+		if page == "" || strings.HasSuffix(page, "\n") {
+			end--
+		}
+		extraction.Pages = append(extraction.Pages, PageText{
+			Number:    i + 1,
+			StartLine: max(line, 1),
+			EndLine:   end,
+			OCR:       ocr[i],
+		})
+		line = next
+	}
+	return extraction
+}
+
+// resolveExtractor picks a concrete extractor for ExtractorAuto.
+func resolveExtractor(extractor Extractor) Extractor {
+	if extractor != ExtractorAuto {
+		return extractor
+	}
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		slog.Debug("pdftotext not found, using native extractor")
+		return ExtractorNative
+	}
+	return ExtractorPdftotext
+}
+
+// runPdftotext runs pdftotext with the given mode flags, writing to stdout.
+func runPdftotext(ctx context.Context, pdfPath string, passwords Passwords, flags ...string) ([]byte, error) {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		return nil, ErrPdftotextNotFound
+	}
+	args := append(popplerPasswordArgs(passwords), flags...)
+	return runTool(ctx, pdfPath, "pdftotext", append(args, pdfPath, "-")...)
+}
+
+// popplerPasswordArgs returns the -opw/-upw flags shared by poppler tools.
+func popplerPasswordArgs(passwords Passwords) []string {
+	var args []string
+	if passwords.Owner != "" {
+		args = append(args, "-opw", string(passwords.Owner))
+	}
+	if passwords.User != "" {
+		args = append(args, "-upw", string(passwords.User))
+	}
+	return args
+}
+
+// runTool runs an external command on target and returns its stdout.
+// Arguments, which may hold passwords, never appear in errors. Failures
+// include the tool's stderr, which explains problems such as encrypted or
+// corrupt files; a cancelled ctx is reported as ctx.Err().
+func runTool(ctx context.Context, target, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
 	output, err := cmd.Output()
 	if err != nil {
-		slog.Error("Failed to run pdftotext", "error", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("%s %s: %w", name, target, ctxErr)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s %s: %w: %s", name, target, err, msg)
+		}
+		return nil, fmt.Errorf("%s %s: %w", name, target, err)
+	}
+	return output, nil
+}
+
+// ReadText returns already-extracted statement text from a file, or from
+// stdin when path is "-". The text is trimmed the same way as ExtractText.
+func ReadText(path string) (string, error) {
+	slog.Debug("Reading statement text", "path", path)
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		slog.Error("Failed to read statement text", "path", path, "error", err)
 		return "", err
 	}
-	text := strings.TrimSpace(string(output))
-	slog.Debug("Extracted text", "length", len(text))
+	text := strings.TrimSpace(string(data))
+	slog.Debug("Read text", "length", len(text))
 	return text, nil
 }
@@ -2,9 +2,13 @@
 package shared
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestExtractText(t *testing.T) {
@@ -28,3 +32,73 @@ func TestExtractText(t *testing.T) {
 		t.Errorf("Expected error for dummy PDF")
 	}
 }
+
+func TestReadText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statement.txt")
+	if err := os.WriteFile(path, []byte("\n  Owner Statement\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadText(path)
+	if err != nil {
+		t.Fatalf("ReadText() error = %v", err)
+	}
+	if got != "Owner Statement" {
+		t.Errorf("ReadText() = %q, want %q", got, "Owner Statement")
+	}
+
+	if _, err := ReadText(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
+
+// fakeTool puts a shell script with the given name and body first on PATH.
+func fakeTool(t *testing.T, name, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestExtractTextPdftotextStderr(t *testing.T) {
+	fakeTool(t, "pdftotext", `echo "Command Line Error: Incorrect password" >&2; exit 1`)
+
+	_, err := ExtractTextWithOptions("locked.pdf", ExtractOptions{Extractor: ExtractorPdftotext})
+	if err == nil {
+		t.Fatal("Expected error from failing pdftotext")
+	}
+	for _, want := range []string{"locked.pdf", "Incorrect password", "exit status 1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestExtractTextContextTimeout(t *testing.T) {
+	fakeTool(t, "pdftotext", "exec sleep 10")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := ExtractTextContext(ctx, "slow.pdf", ExtractOptions{Extractor: ExtractorPdftotext})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ExtractTextContext() error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ExtractTextContext() took %v after the deadline", elapsed)
+	}
+}
+
+func TestExtractTextContextCancelledNative(t *testing.T) {
+	pdfPath := filepath.Join(t.TempDir(), "statement.pdf")
+	writeTestPDF(t, pdfPath, "Owner Statement")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ExtractTextContext(ctx, pdfPath, ExtractOptions{Extractor: ExtractorNative}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExtractTextContext() error = %v, want context canceled", err)
+	}
+}
@@ -0,0 +1,243 @@
+// internal/shared/history.go
+package shared
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// History is what an existing Beancount ledger says about how entries were
+// categorized: its transactions and open accounts.
+type History struct {
+	Entries []HistoryEntry
+	// Open lists the accounts opened in the ledger, in file order.
+	Open []string
+}
+
+// HistoryEntry is a past transaction.
+type HistoryEntry struct {
+	Payee     string
+	Narration string
+	// Postings hold the accounts and amounts; an elided amount is empty.
+	Postings []parser.Posting
+}
+
+var (
+	// transactionRe matches the first line of a Beancount transaction.
+	transactionRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\s+(?:\*|!|txn)\s+(.*)$`)
+	// quotedRe matches a Beancount string.
+	quotedRe = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	// postingRe matches a posting's account and amount; metadata keys are
+	// lower case.
+	postingRe = regexp.MustCompile(`^\s+[!*]?\s*([A-Z][A-Za-z0-9-]*(?::[A-Za-z0-9][A-Za-z0-9-]*)+)(?:\s+(-?[\d,]*\.?\d+)\s+([A-Z][A-Z0-9'._-]*))?`)
+	openRe    = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\s+open\s+(\S+)`)
+)
+
+// LoadHistory reads the transactions and open directives in Beancount
+// files and the files they include. Empty paths are skipped.
+func LoadHistory(paths ...string) (*History, error) {
+	history := &History{}
+	seen := map[string]bool{}
+	var current *HistoryEntry
+	flush := func() {
+		if current != nil {
+			history.Entries = append(history.Entries, *current)
+			current = nil
+		}
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		err := walkLedger(path, seen, func(_ string, _ int, line string) {
+			if current != nil && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+				if m := postingRe.FindStringSubmatch(line); m != nil {
+					amount := parser.Amount{Value: strings.ReplaceAll(m[2], ",", ""), Currency: m[3]}
+					current.Postings = append(current.Postings, parser.Posting{Account: m[1], Amount: amount})
+				}
+				return
+			}
+			flush()
+			if m := transactionRe.FindStringSubmatch(line); m != nil {
+				current = &HistoryEntry{}
+				var texts []string
+				for _, quoted := range quotedRe.FindAllString(m[1], 2) {
+					texts = append(texts, unquote(quoted))
+				}
+				switch len(texts) {
+				case 1:
+					current.Narration = texts[0]
+				case 2:
+					current.Payee, current.Narration = texts[0], texts[1]
+				}
+			} else if m := openRe.FindStringSubmatch(line); m != nil {
+				history.Open = append(history.Open, m[1])
+			}
+		})
+		flush()
+		if err != nil {
+			return nil, err
+		}
+	}
+	slog.Debug("Loaded ledger history", "entries", len(history.Entries), "open_accounts", len(history.Open))
+	return history, nil
+}
+
+// Suggest returns up to limit accounts for an uncategorized transaction,
+// best first: accounts that past transactions with words in common used,
+// weighted by the words shared, then open accounts whose names share a
+// word with the transaction. The transaction's own accounts and the
+// suspense account are never suggested.
+func (h *History) Suggest(tx *parser.Transaction, limit int) []string {
+	words := descriptionWords(tx)
+	skip := map[string]bool{parser.SuspenseAccount: true}
+	for _, p := range tx.Postings {
+		skip[p.Account] = true
+	}
+
+	scores := map[string]int{}
+	for _, entry := range h.Entries {
+		common := 0
+		for word := range descriptionWords(&parser.Transaction{Payee: entry.Payee, Narration: entry.Narration}) {
+			if words[word] {
+				common++
+			}
+		}
+		if common == 0 {
+			continue
+		}
+		for _, p := range entry.Postings {
+			if !skip[p.Account] {
+				scores[p.Account] += common
+			}
+		}
+	}
+	var suggestions []string
+	for account := range scores {
+		suggestions = append(suggestions, account)
+	}
+	slices.SortFunc(suggestions, func(a, b string) int {
+		return cmp.Or(cmp.Compare(scores[b], scores[a]), cmp.Compare(a, b))
+	})
+
+	for _, account := range h.Open {
+		if skip[account] || scores[account] > 0 {
+			continue
+		}
+		for _, word := range splitWords(account) {
+			if words[word] {
+				suggestions = append(suggestions, account)
+				break
+			}
+		}
+	}
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// IsOpen reports whether account is opened in the history. With no open
+// directives every account counts as open.
+func (h *History) IsOpen(account string) bool {
+	return len(h.Open) == 0 || slices.Contains(h.Open, account)
+}
+
+// stopWords are left out when comparing descriptions.
+var stopWords = map[string]bool{"memo": true, "the": true, "and": true, "for": true}
+
+// descriptionWords returns the lower-cased words of tx's payee and
+// narration.
+func descriptionWords(tx *parser.Transaction) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range splitWords(tx.Payee + " " + tx.Narration) {
+		words[word] = true
+	}
+	return words
+}
+
+// splitWords splits text into lower-cased words of three or more letters
+// or digits, dropping stop words. Account names split at colons and
+// hyphens.
+func splitWords(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) >= 3 && !stopWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// walkLedger calls visit with each line of the ledger file at path, then
+// walks the files each include line names. Include patterns are resolved
+// relative to the including file and may be globs. Files in seen are
+// skipped, and each file walked is added to it.
+func walkLedger(path string, seen map[string]bool, visit func(path string, lineNum int, line string)) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if seen[abs] {
+		return nil
+	}
+	seen[abs] = true
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		visit(path, lineNum, line)
+		m := includeRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		pattern := unquote(strings.TrimSpace(m[1]))
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err == nil && len(matches) == 0 {
+			err = fmt.Errorf("include %s: no such file", pattern)
+		}
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, lineNum, err)
+		}
+		for _, match := range matches {
+			if err := walkLedger(match, seen, visit); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	return nil
+}
+
+// unquote removes the double quotes around a Beancount string, leaving
+// other values unchanged.
+func unquote(value string) string {
+	if s, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return s
+	}
+	return value
+}
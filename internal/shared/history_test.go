@@ -0,0 +1,99 @@
+// internal/shared/history_test.go
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestLoadHistory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"header.bean": "2025-01-01 open Assets:PM USD\n2025-01-01 open Expenses:Pest-Control USD\n2025-01-01 open Expenses:Landscaping USD\n",
+		"main.bean":   "include \"2025/*.bean\"\n\n2025-02-01 * \"Green Yards\" \"Memo: Mowing\"\n  review: \"checked\"\n  Expenses:Landscaping  40.00 USD\n  Assets:PM\n",
+		"2025/q1.bean": "2025-03-01 ! \"Pest Control\" \"Memo: Quarterly service\"\n  Expenses:Pest-Control  95.00 USD\n  Assets:PM  -95.00 USD\n" +
+			"2025-03-02 txn \"Owner draw\"\n  Equity:Owner-Draws  1,000.00 USD\n  Assets:PM\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := LoadHistory(filepath.Join(dir, "main.bean"), "", filepath.Join(dir, "header.bean"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	usd := func(value string) parser.Amount { return parser.Amount{Value: value, Currency: "USD"} }
+	wantEntries := []HistoryEntry{
+		{Payee: "Pest Control", Narration: "Memo: Quarterly service", Postings: []parser.Posting{{Account: "Expenses:Pest-Control", Amount: usd("95.00")}, {Account: "Assets:PM", Amount: usd("-95.00")}}},
+		{Narration: "Owner draw", Postings: []parser.Posting{{Account: "Equity:Owner-Draws", Amount: usd("1000.00")}, {Account: "Assets:PM"}}},
+		{Payee: "Green Yards", Narration: "Memo: Mowing", Postings: []parser.Posting{{Account: "Expenses:Landscaping", Amount: usd("40.00")}, {Account: "Assets:PM"}}},
+	}
+	if !reflect.DeepEqual(history.Entries, wantEntries) {
+		t.Errorf("Entries = %+v, want %+v", history.Entries, wantEntries)
+	}
+	wantOpen := []string{"Assets:PM", "Expenses:Pest-Control", "Expenses:Landscaping"}
+	if !reflect.DeepEqual(history.Open, wantOpen) {
+		t.Errorf("Open = %q, want %q", history.Open, wantOpen)
+	}
+	if !history.IsOpen("Expenses:Landscaping") || history.IsOpen("Expenses:Pool") {
+		t.Errorf("IsOpen() disagrees with the open directives")
+	}
+
+	if _, err := LoadHistory(filepath.Join(dir, "missing.bean")); err == nil {
+		t.Errorf("LoadHistory(missing) succeeded")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	posting := func(account string) parser.Posting { return parser.Posting{Account: account} }
+	history := &History{
+		Entries: []HistoryEntry{
+			{Payee: "Pest Control", Narration: "Memo: Quarterly pest service", Postings: []parser.Posting{posting("Expenses:Pest-Control"), posting("Assets:PM")}},
+			{Payee: "Acme Services", Narration: "Memo: Gutter service", Postings: []parser.Posting{posting("Expenses:Repairs"), posting("Assets:PM")}},
+			{Payee: "Green Yards", Narration: "Memo: Mowing", Postings: []parser.Posting{posting("Expenses:Landscaping"), posting("Assets:PM")}},
+		},
+		Open: []string{"Assets:PM", "Expenses:Repairs", "Expenses:Gutter-Cleaning", "Expenses:Utilities"},
+	}
+
+	tests := []struct {
+		name  string
+		tx    *parser.Transaction
+		limit int
+		want  []string
+	}{
+		{
+			name:  "history by shared words, then open accounts",
+			tx:    &parser.Transaction{Payee: "Pest Control", Narration: "Memo: Gutter service", Postings: []parser.Posting{posting("Assets:PM"), posting(parser.SuspenseAccount)}},
+			limit: 5,
+			want:  []string{"Expenses:Pest-Control", "Expenses:Repairs", "Expenses:Gutter-Cleaning"},
+		},
+		{
+			name:  "limit",
+			tx:    &parser.Transaction{Payee: "Pest Control", Narration: "Memo: Gutter service", Postings: []parser.Posting{posting("Assets:PM"), posting(parser.SuspenseAccount)}},
+			limit: 1,
+			want:  []string{"Expenses:Pest-Control"},
+		},
+		{
+			name:  "nothing in common",
+			tx:    &parser.Transaction{Payee: "Roofer", Postings: []parser.Posting{posting("Assets:PM"), posting(parser.SuspenseAccount)}},
+			limit: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := history.Suggest(tt.tx, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
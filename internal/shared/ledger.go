@@ -0,0 +1,102 @@
+// internal/shared/ledger.go
+package shared
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// balanceAssertionPayee describes the zero-amount entries that carry
+// balance assertions in ledger and hledger journals.
+const balanceAssertionPayee = "Balance assertion"
+
+// writeLedger renders txs as a ledger-cli or hledger journal.
+//
+// Beancount concepts are translated as follows:
+//   - balance directives become a zero-amount posting with a "= AMOUNT" assertion;
+//   - note directives, which neither tool has, become top-level comments;
+//   - payee and narration become hledger's "payee | note" description, or for
+//     ledger-cli a narration description with a "Payee:" metadata override;
+//   - tags become ":a:b:" (ledger) or "a:, b:" (hledger) comment tags;
+//   - metadata becomes "key: value" comment lines.
+//
+// Account names are written unchanged; Beancount's naming rules are a subset
+// of what both tools accept.
+func writeLedger(w io.Writer, txs []*parser.Transaction, format OutputFormat) error {
+	var b strings.Builder
+	accountWidth, amountWidth := ComputePostingWidths(txs)
+	for _, tx := range txs {
+		date := ledgerDate(tx.Date, format)
+		if tx.Directive == "balance" {
+			fmt.Fprintf(&b, "%s * %s\n", date, balanceAssertionPayee)
+			zero := parser.Posting{Account: tx.BalanceAccount, Amount: parser.Amount{Value: "0", Currency: tx.BalanceAmount.Currency}}
+			fmt.Fprintf(&b, "%s = %s %s\n", formatPostingLine(zero, accountWidth, amountWidth), tx.BalanceAmount.Value, tx.BalanceAmount.Currency)
+			fmt.Fprintln(&b)
+			continue
+		}
+		if tx.Directive == "note" {
+			fmt.Fprintf(&b, "; %s note %s: %s\n", date, tx.NoteAccount, tx.Narration)
+			for _, key := range sortedLinkKeys(tx.Links) {
+				fmt.Fprintln(&b, strings.TrimRight(fmt.Sprintf(";   %s: %s", key, tx.Links[key]), " "))
+			}
+			fmt.Fprintln(&b)
+			continue
+		}
+
+		description, payeeOverride := ledgerDescription(tx, format)
+		fmt.Fprintf(&b, "%s %s %s\n", date, entryFlag(tx), description)
+		if payeeOverride != "" {
+			fmt.Fprintf(&b, "  ; Payee: %s\n", payeeOverride)
+		}
+		if tags := ledgerTags(tx.Tags, format); tags != "" {
+			fmt.Fprintf(&b, "  ; %s\n", tags)
+		}
+		for _, key := range sortedLinkKeys(tx.Links) {
+			fmt.Fprintln(&b, strings.TrimRight(fmt.Sprintf("  ; %s: %s", key, tx.Links[key]), " "))
+		}
+		for _, p := range tx.Postings {
+			fmt.Fprintln(&b, formatPostingLine(p, accountWidth, amountWidth))
+		}
+		fmt.Fprintln(&b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ledgerDate converts a YYYY-MM-DD date to the format's preferred separator.
+func ledgerDate(date string, format OutputFormat) string {
+	if format == OutputLedger {
+		return strings.ReplaceAll(date, "-", "/")
+	}
+	return date
+}
+
+// ledgerDescription returns the transaction description and, for ledger-cli,
+// the payee to record as metadata when it differs from the description.
+func ledgerDescription(tx *parser.Transaction, format OutputFormat) (string, string) {
+	if tx.Narration == "" {
+		return tx.Payee, ""
+	}
+	if format == OutputHledger {
+		return tx.Payee + " | " + tx.Narration, ""
+	}
+	return tx.Narration, tx.Payee
+}
+
+// ledgerTags renders Beancount tags in the format's comment tag syntax.
+func ledgerTags(tags []string, format OutputFormat) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = strings.TrimPrefix(tag, "#")
+	}
+	if format == OutputLedger {
+		return ":" + strings.Join(names, ":") + ":"
+	}
+	return strings.Join(names, ":, ") + ":"
+}
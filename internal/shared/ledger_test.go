@@ -0,0 +1,76 @@
+// internal/shared/ledger_test.go
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestWriteLedger(t *testing.T) {
+	txs := []*parser.Transaction{
+		{
+			Date:           "2025-11-01",
+			Directive:      "balance",
+			BalanceAccount: "Assets:Cash",
+			BalanceAmount:  parser.Amount{Value: "100.00", Currency: "USD"},
+		},
+		{
+			Date:      "2025-11-02",
+			Payee:     "Tenant",
+			Narration: "Rent",
+			Tags:      []string{"#imported", "#reversed"},
+			Links:     map[string]string{"comments": "", "source": "statement"},
+			Postings: []parser.Posting{
+				{Account: "Income:Rent", Amount: parser.Amount{Value: "-50.00", Currency: "USD"}},
+				{Account: "Assets:Cash", Amount: parser.Amount{Value: "50.00", Currency: "USD"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		format OutputFormat
+		want   []string
+	}{
+		{
+			format: OutputLedger,
+			want: []string{
+				"2025/11/01 * Balance assertion",
+				"0 USD = 100.00 USD",
+				"2025/11/02 * Rent",
+				"  ; Payee: Tenant",
+				"  ; :imported:reversed:",
+				"  ; comments:\n  ; source: statement",
+			},
+		},
+		{
+			format: OutputHledger,
+			want: []string{
+				"2025-11-01 * Balance assertion",
+				"0 USD = 100.00 USD",
+				"2025-11-02 * Tenant | Rent",
+				"  ; imported:, reversed:",
+				"  ; comments:\n  ; source: statement",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteEntries(&b, txs, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			got := b.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %q:\n%s", want, got)
+				}
+			}
+			if tt.format == OutputHledger && strings.Contains(got, "Payee:") {
+				t.Errorf("hledger output should carry the payee in the description:\n%s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// internal/shared/native.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/jason-riddle/ledger-go/internal/layout"
+	"github.com/ledongthuc/pdf"
+)
+
+// NativeExtractorVersion changes whenever the native layout output changes.
+const NativeExtractorVersion = 1
+
+// extractNativeLayout reads positioned glyphs with a pure-Go PDF parser and
+// merges them into words and lines, checking ctx before each page.
+// Encrypted files are opened by trying the user password, then the owner
+// password, as the user password; owner-only access and 40-bit RC4 files
+// need pdftotext.
+func extractNativeLayout(ctx context.Context, pdfPath string, passwords Passwords) (doc *layout.Document, err error) {
+	slog.Debug("Extracting text natively", "path", pdfPath)
+	file, err := os.Open(pdfPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// The PDF reader reports malformed input by panicking.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("read PDF %s: %v", pdfPath, r)
+		}
+	}()
+
+	candidates := []Secret{passwords.User, passwords.Owner}
+	reader, err := pdf.NewReaderEncrypted(file, info.Size(), func() string {
+		for len(candidates) > 0 {
+			next := candidates[0]
+			candidates = candidates[1:]
+			if next != "" {
+				return string(next)
+			}
+		}
+		return ""
+	})
+	if errors.Is(err, pdf.ErrInvalidPassword) {
+		if passwords.IsZero() {
+			return nil, fmt.Errorf("read PDF %s: %w (set %s)", pdfPath, ErrEncryptedPDF, PasswordEnv)
+		}
+		return nil, fmt.Errorf("read PDF %s: %w", pdfPath, ErrWrongPassword)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read PDF %s: %w", pdfPath, err)
+	}
+
+	doc = &layout.Document{}
+	for i := 1; i <= reader.NumPage(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		doc.Pages = append(doc.Pages, nativePage(i, page))
+	}
+	return doc, nil
+}
+
+// nativePage converts a page's glyphs to top-left coordinates, groups them
+// into lines and merges touching glyphs into words.
+func nativePage(number int, page pdf.Page) layout.Page {
+	texts := page.Content().Text
+	width, height := mediaBoxSize(page.V)
+	if height == 0 {
+		for _, t := range texts {
+			height = math.Max(height, t.Y+t.FontSize)
+		}
+	}
+
+	var glyphs []layout.Word
+	for _, t := range texts {
+		if strings.TrimFunc(t.S, unicode.IsSpace) == "" {
+			continue
+		}
+		size := t.FontSize
+		if size <= 0 {
+			size = 10
+		}
+		w := t.W
+		if w <= 0 {
+			w = 0.6 * size
+		}
+		glyphs = append(glyphs, layout.Word{
+			Text: t.S,
+			XMin: t.X,
+			XMax: t.X + w,
+			YMin: height - t.Y - size,
+			YMax: height - t.Y,
+		})
+	}
+
+	result := layout.Page{Number: number, Width: width, Height: height}
+	for _, line := range layout.GroupLines(glyphs) {
+		result.Lines = append(result.Lines, mergeGlyphs(line))
+	}
+	return result
+}
+
+// mergeGlyphs joins glyphs separated by less than a third of a character
+// into words; any wider gap starts a new word.
+func mergeGlyphs(line layout.Line) layout.Line {
+	var merged layout.Line
+	for _, g := range line.Words {
+		if n := len(merged.Words); n > 0 {
+			last := &merged.Words[n-1]
+			if g.XMin-last.XMax <= 0.3*(g.XMax-g.XMin) {
+				last.Text += g.Text
+				last.XMax = math.Max(last.XMax, g.XMax)
+				last.YMin = math.Min(last.YMin, g.YMin)
+				last.YMax = math.Max(last.YMax, g.YMax)
+				continue
+			}
+		}
+		merged.Words = append(merged.Words, g)
+	}
+	return merged
+}
+
+// mediaBoxSize returns the page size, following MediaBox entries inherited
+// from the page tree.
+func mediaBoxSize(v pdf.Value) (float64, float64) {
+	for depth := 0; !v.IsNull() && depth < 32; depth++ {
+		box := v.Key("MediaBox")
+		if box.Kind() == pdf.Array && box.Len() == 4 {
+			return box.Index(2).Float64() - box.Index(0).Float64(), box.Index(3).Float64() - box.Index(1).Float64()
+		}
+		v = v.Key("Parent")
+	}
+	return 0, 0
+}
@@ -0,0 +1,347 @@
+// internal/shared/native_test.go
+package shared
+
+import (
+	"crypto/md5"
+	"crypto/rc4"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/cloverleaf"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+	"github.com/jason-riddle/ledger-go/internal/sheervalue"
+)
+
+var nativeFixtures = []struct {
+	name   string
+	path   string
+	parser func() parser.Parser
+}{
+	{
+		name:   "cloverleaf",
+		path:   filepath.Join("..", "..", "tests", "fixtures", "cloverleaf", "cloverleaf_2025-12-11_statement.txt"),
+		parser: cloverleaf.NewParser,
+	},
+	{
+		name:   "sheervalue",
+		path:   filepath.Join("..", "..", "tests", "fixtures", "sheervalue", "multi_prop", "sheervalue_2025_multi_property_statement.txt"),
+		parser: sheervalue.NewParser,
+	},
+	{
+		name: "sps",
+		path: filepath.Join("..", "..", "tests", "fixtures", "sps", "sps_2023-11-14_mortgage.txt"),
+	},
+}
+
+// TestExtractNativeFixtures renders each fixture into a monospaced PDF and
+// checks the native extractor recovers text the parsers treat identically.
+func TestExtractNativeFixtures(t *testing.T) {
+	for _, fixture := range nativeFixtures {
+		t.Run(fixture.name, func(t *testing.T) {
+			data, err := os.ReadFile(fixture.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := renderableText(strings.TrimSpace(string(data)))
+
+			pdfPath := filepath.Join(t.TempDir(), fixture.name+".pdf")
+			writeTestPDF(t, pdfPath, want)
+
+			got, err := ExtractTextWithOptions(pdfPath, ExtractOptions{Extractor: ExtractorNative})
+			if err != nil {
+				t.Fatalf("native extraction failed: %v", err)
+			}
+			if normalizeLayout(got) != normalizeLayout(want) {
+				t.Errorf("native text differs from fixture.\nGot:\n%s\n\nWant:\n%s", got, want)
+			}
+
+			if fixture.parser == nil {
+				return
+			}
+			wantTxs, err := fixture.parser().Parse(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotTxs, err := fixture.parser().Parse(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotTxs, wantTxs) {
+				t.Errorf("parsed native text differs from parsed fixture: got %d entries, want %d", len(gotTxs), len(wantTxs))
+			}
+		})
+	}
+}
+
+// TestExtractNativeMatchesPdftotext compares both extractors on the same PDF.
+func TestExtractNativeMatchesPdftotext(t *testing.T) {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		t.Skip("pdftotext not available")
+	}
+	for _, fixture := range nativeFixtures {
+		t.Run(fixture.name, func(t *testing.T) {
+			data, err := os.ReadFile(fixture.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pdfPath := filepath.Join(t.TempDir(), fixture.name+".pdf")
+			writeTestPDF(t, pdfPath, strings.TrimSpace(string(data)))
+
+			native, err := ExtractTextWithOptions(pdfPath, ExtractOptions{Extractor: ExtractorNative})
+			if err != nil {
+				t.Fatal(err)
+			}
+			poppler, err := ExtractTextWithOptions(pdfPath, ExtractOptions{Extractor: ExtractorPdftotext})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if normalizeLayout(native) != normalizeLayout(poppler) {
+				t.Errorf("native and pdftotext output differ.\nNative:\n%s\n\nPdftotext:\n%s", native, poppler)
+			}
+		})
+	}
+}
+
+func TestExtractNativeInvalidPDF(t *testing.T) {
+	pdfPath := filepath.Join(t.TempDir(), "test.pdf")
+	os.WriteFile(pdfPath, []byte("dummy"), 0644)
+
+	if _, err := ExtractTextWithOptions(pdfPath, ExtractOptions{Extractor: ExtractorNative}); err == nil {
+		t.Errorf("Expected error for dummy PDF")
+	}
+}
+
+func TestParseExtractor(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Extractor
+		wantErr bool
+	}{
+		{value: "", want: ExtractorAuto},
+		{value: "auto", want: ExtractorAuto},
+		{value: "pdftotext", want: ExtractorPdftotext},
+		{value: "native", want: ExtractorNative},
+		{value: "ocr", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseExtractor(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExtractor(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParseExtractor(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// normalizeLayout collapses horizontal whitespace and drops blank lines so
+// texts that differ only in margins and column padding compare equal.
+func normalizeLayout(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\f", "\n"), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, strings.Join(fields, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeTestPDF renders layout text into a PDF using 10pt Courier, one page
+// per form-feed separated section, placing each word at its text column.
+func writeTestPDF(t *testing.T, path, text string) {
+	t.Helper()
+	writeEncryptedTestPDF(t, path, text, Passwords{})
+}
+
+// writeEncryptedTestPDF is writeTestPDF protected with the standard 128-bit
+// RC4 security handler (PDF 1.4, revision 3) when any password is set.
+func writeEncryptedTestPDF(t *testing.T, path, text string, passwords Passwords) {
+	t.Helper()
+	const (
+		margin     = 36.0
+		charWidth  = 6.0
+		lineHeight = 12.0
+	)
+
+	pages := strings.Split(text, "\f")
+	var objects []string
+	streams := map[int]string{}
+	add := func(body string) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+
+	catalog := add("")
+	pagesObj := add("")
+	widths := strings.TrimSpace(strings.Repeat("600 ", 224))
+	font := add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding /FirstChar 32 /LastChar 255 /Widths [%s] >>", widths))
+
+	var kids []string
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		maxCols := 1
+		for _, line := range lines {
+			maxCols = max(maxCols, len([]rune(line)))
+		}
+		width := 2*margin + float64(maxCols)*charWidth
+		height := 2*margin + float64(len(lines))*lineHeight
+
+		var content strings.Builder
+		content.WriteString("BT /F1 10 Tf\n")
+		for i, line := range lines {
+			y := height - margin - float64(i+1)*lineHeight
+			runes := []rune(line)
+			for col := 0; col < len(runes); {
+				if runes[col] == ' ' {
+					col++
+					continue
+				}
+				end := col
+				for end < len(runes) && runes[end] != ' ' {
+					end++
+				}
+				x := margin + float64(col)*charWidth
+				fmt.Fprintf(&content, "1 0 0 1 %.2f %.2f Tm (%s) Tj\n", x, y, pdfString(runes[col:end]))
+				col = end
+			}
+		}
+		content.WriteString("ET\n")
+
+		stream := add("")
+		streams[stream] = content.String()
+		pageObj := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, width, height, font, stream))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)
+	objects[pagesObj-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	id := []byte("lgo-test-document")
+	trailer := fmt.Sprintf("/ID [<%x> <%x>]", id, id)
+	var key []byte
+	if !passwords.IsZero() {
+		var o, u []byte
+		key, o, u = rc4SecurityHandler(string(passwords.User), string(passwords.Owner), id)
+		trailer += fmt.Sprintf(" /Encrypt << /Filter /Standard /V 2 /R 3 /Length 128 /P -44 /O <%x> /U <%x> >>", o, u)
+	}
+	for obj, data := range streams {
+		if key != nil {
+			data = rc4Object(key, obj, data)
+		}
+		objects[obj-1] = fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+	}
+
+	var out strings.Builder
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, trailer, xref)
+
+	if err := os.WriteFile(path, []byte(out.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// pdfPasswordPad is the padding string from PDF 32000-1:2008, algorithm 2.
+var pdfPasswordPad = []byte("\x28\xbf\x4e\x5e\x4e\x75\x8a\x41\x64\x00\x4e\x56\xff\xfa\x01\x08" +
+	"\x2e\x2e\x00\xb6\xd0\x68\x3e\x80\x2f\x0c\xa9\xfe\x64\x53\x69\x7a")
+
+// rc4SecurityHandler derives the revision 3 file key and the O and U entries
+// (PDF 32000-1:2008, algorithms 2, 3 and 5) for 128-bit keys and P = -44.
+func rc4SecurityHandler(user, owner string, id []byte) (key, o, u []byte) {
+	pad := func(pw string) []byte {
+		return append([]byte(pw), pdfPasswordPad...)[:32]
+	}
+	stretch := func(sum [16]byte) []byte {
+		for range 50 {
+			sum = md5.Sum(sum[:])
+		}
+		return sum[:]
+	}
+	rounds := func(key, data []byte) []byte {
+		data = rc4XOR(key, data)
+		for i := 1; i <= 19; i++ {
+			xored := make([]byte, len(key))
+			for j := range key {
+				xored[j] = key[j] ^ byte(i)
+			}
+			data = rc4XOR(xored, data)
+		}
+		return data
+	}
+
+	if owner == "" {
+		owner = user
+	}
+	o = rounds(stretch(md5.Sum(pad(owner))), pad(user))
+
+	p := uint32(0xffffffd4)
+	seed := append(append(pad(user), o...), byte(p), byte(p>>8), byte(p>>16), byte(p>>24))
+	key = stretch(md5.Sum(append(seed, id...)))
+
+	check := md5.Sum(append(append([]byte{}, pdfPasswordPad...), id...))
+	u = append(rounds(key, check[:]), make([]byte, 16)...)
+	return key, o, u
+}
+
+// rc4Object encrypts data belonging to object obj (generation 0), algorithm 1.
+func rc4Object(key []byte, obj int, data string) string {
+	objKey := md5.Sum(append(append([]byte{}, key...), byte(obj), byte(obj>>8), byte(obj>>16), 0, 0))
+	return string(rc4XOR(objKey[:], []byte(data)))
+}
+
+func rc4XOR(key, data []byte) []byte {
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
+
+// renderableText replaces runes writeTestPDF cannot encode with '?', so the
+// extracted text can be compared with what was actually drawn.
+func renderableText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 128 || r == '–' || r == '—' {
+			return r
+		}
+		return '?'
+	}, text)
+}
+
+// pdfString escapes runes for a WinAnsi-encoded PDF literal string.
+func pdfString(runes []rune) string {
+	var b strings.Builder
+	for _, r := range renderableText(string(runes)) {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '–':
+			b.WriteString(`\226`)
+		case r == '—':
+			b.WriteString(`\227`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+// internal/shared/ocr.go
+package shared
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/layout"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// OCRDPI is the resolution pages are rendered at for OCR.
+const OCRDPI = 300
+
+var (
+	// ErrNoTextLayer is returned when a PDF yields no text, usually because
+	// it is a scan; OCR can recover the text.
+	ErrNoTextLayer = errors.New("PDF has no text layer (scanned?); rerun with --ocr")
+	// ErrOCRToolNotFound is returned when OCR is enabled but pdftoppm or
+	// tesseract is not on PATH.
+	ErrOCRToolNotFound = errors.New("OCR tool not found on PATH (install poppler-utils and tesseract)")
+)
+
+// ocrPage renders one page to an image with pdftoppm and recognizes it with
+// tesseract, returning the words in PDF points.
+func ocrPage(ctx context.Context, pdfPath string, number int, passwords Passwords) (layout.Page, error) {
+	for _, tool := range []string{"pdftoppm", "tesseract"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return layout.Page{}, fmt.Errorf("%w: %s", ErrOCRToolNotFound, tool)
+		}
+	}
+	slog.Debug("Running OCR", "path", pdfPath, "page", number, "dpi", OCRDPI)
+
+	dir, err := os.MkdirTemp("", "lgo-ocr-")
+	if err != nil {
+		return layout.Page{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	prefix := filepath.Join(dir, "page")
+	n := strconv.Itoa(number)
+	args := append(popplerPasswordArgs(passwords), "-r", strconv.Itoa(OCRDPI), "-gray", "-png", "-f", n, "-l", n, "-singlefile", pdfPath, prefix)
+	if _, err := runTool(ctx, pdfPath, "pdftoppm", args...); err != nil {
+		return layout.Page{}, err
+	}
+
+	image := prefix + ".png"
+	output, err := runTool(ctx, image, "tesseract", image, "stdout", "--psm", "6", "tsv")
+	if err != nil {
+		return layout.Page{}, err
+	}
+	page, err := parseTesseractTSV(bytes.NewReader(output), OCRDPI)
+	if err != nil {
+		return layout.Page{}, fmt.Errorf("OCR page %d of %s: %w", number, pdfPath, err)
+	}
+	page.Number = number
+	return page, nil
+}
+
+// parseTesseractTSV reads tesseract's TSV output, converting pixel boxes at
+// the given resolution to points. Page rows (level 1) give the page size and
+// word rows (level 5) the words, which are regrouped into visual lines.
+func parseTesseractTSV(r io.Reader, dpi int) (layout.Page, error) {
+	scale := 72 / float64(dpi)
+	var page layout.Page
+	var words []layout.Word
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if lineNum == 1 && len(fields) > 0 && fields[0] == "level" {
+			continue
+		}
+		if len(fields) < 11 {
+			continue
+		}
+		var box [4]float64
+		for i := range box {
+			v, err := strconv.Atoi(fields[6+i])
+			if err != nil {
+				return layout.Page{}, fmt.Errorf("tsv line %d: bad box: %w", lineNum, err)
+			}
+			box[i] = float64(v) * scale
+		}
+		left, top, width, height := box[0], box[1], box[2], box[3]
+		switch fields[0] {
+		case "1":
+			page.Width, page.Height = width, height
+		case "5":
+			text := ""
+			if len(fields) > 11 {
+				text = strings.TrimSpace(fields[11])
+			}
+			if text == "" {
+				continue
+			}
+			words = append(words, layout.Word{Text: text, XMin: left, YMin: top, XMax: left + width, YMax: top + height})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return layout.Page{}, err
+	}
+	page.Lines = layout.GroupLines(words)
+	return page, nil
+}
+
+// FlagOCRTransactions marks transactions that came from OCR'd pages with
+// the review flag and a review reason, returning how many were marked.
+// Balance and note directives cannot carry a flag and are left unchanged.
+func FlagOCRTransactions(txs []*parser.Transaction, extraction *Extraction) int {
+	marked := 0
+	for _, tx := range txs {
+		if tx.Directive == "balance" || tx.Directive == "note" || tx.Line == 0 {
+			continue
+		}
+		page := extraction.PageAt(tx.Line)
+		if page == nil || !page.OCR {
+			continue
+		}
+		tx.MarkForReview(fmt.Sprintf("OCR page %d", page.Number))
+		marked++
+	}
+	if marked > 0 {
+		slog.Warn("Transactions from OCR pages are flagged for review", "count", marked)
+	}
+	return marked
+}
@@ -0,0 +1,144 @@
+// internal/shared/ocr_test.go
+package shared
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// sampleTesseractTSV is `tesseract page.png stdout --psm 6 tsv` output for a
+// 300 dpi scan with two rows, the second split into two blocks.
+const sampleTesseractTSV = "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0\t0\t2550\t3300\t-1\t\n" +
+	"2\t1\t1\t0\t0\t0\t150\t150\t600\t100\t-1\t\n" +
+	"5\t1\t1\t1\t1\t1\t150\t150\t400\t50\t96.5\tRent\n" +
+	"5\t1\t1\t1\t1\t2\t600\t150\t200\t50\t95.1\tIncome\n" +
+	"5\t1\t1\t1\t2\t1\t150\t200\t250\t50\t91.0\t11/05/2025\n" +
+	"5\t1\t2\t1\t1\t1\t1000\t202\t250\t50\t88.2\t1,200.00\n" +
+	"5\t1\t2\t1\t1\t2\t1300\t202\t50\t50\t-1\t \n"
+
+func TestParseTesseractTSV(t *testing.T) {
+	page, err := parseTesseractTSV(strings.NewReader(sampleTesseractTSV), 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Width != 612 || page.Height != 792 {
+		t.Errorf("page size = %gx%g, want 612x792", page.Width, page.Height)
+	}
+
+	var got []string
+	for _, line := range page.Lines {
+		got = append(got, line.Text())
+	}
+	want := []string{"Rent Income", "11/05/2025 1,200.00"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+	if w := page.Lines[1].Words[1]; w.XMin != 240 || w.XMax != 300 {
+		t.Errorf("amount x = [%g, %g], want [240, 300]", w.XMin, w.XMax)
+	}
+}
+
+func TestNewExtractionPages(t *testing.T) {
+	pages := []string{"\n\nHeader\nRow 1\n", "", "Row 2\nRow 3\n", "Total\n"}
+	extraction := newExtraction(pages, []bool{false, false, true, false})
+
+	if want := "Header\nRow 1\n\f\fRow 2\nRow 3\n\fTotal"; extraction.Text != want {
+		t.Fatalf("Text = %q, want %q", extraction.Text, want)
+	}
+	lines := strings.Split(extraction.Text, "\n")
+	tests := []struct {
+		text string
+		page int
+		ocr  bool
+	}{
+		{text: "Header", page: 1},
+		{text: "Row 1", page: 1},
+		{text: "\f\fRow 2", page: 3, ocr: true},
+		{text: "Row 3", page: 3, ocr: true},
+		{text: "\fTotal", page: 4},
+	}
+	for _, tt := range tests {
+		line := 0
+		for i, l := range lines {
+			if l == tt.text {
+				line = i + 1
+			}
+		}
+		page := extraction.PageAt(line)
+		if page == nil || page.Number != tt.page || page.OCR != tt.ocr {
+			t.Errorf("PageAt(%d) for %q = %+v, want page %d (ocr %v)", line, tt.text, page, tt.page, tt.ocr)
+		}
+	}
+}
+
+func TestExtractContextNoTextLayer(t *testing.T) {
+	dir := t.TempDir()
+	scanned := filepath.Join(dir, "scanned.pdf")
+	writeTestPDF(t, scanned, "\f")
+	mixed := filepath.Join(dir, "mixed.pdf")
+	writeTestPDF(t, mixed, "Owner Statement\f")
+
+	opts := ExtractOptions{Extractor: ExtractorNative}
+	if _, err := ExtractContext(context.Background(), scanned, opts); !errors.Is(err, ErrNoTextLayer) {
+		t.Errorf("scanned PDF error = %v, want ErrNoTextLayer", err)
+	}
+	extraction, err := ExtractContext(context.Background(), mixed, opts)
+	if err != nil {
+		t.Fatalf("mixed PDF error = %v", err)
+	}
+	if extraction.Text != "Owner Statement" || len(extraction.Pages) != 2 {
+		t.Errorf("mixed PDF = %q with %d pages", extraction.Text, len(extraction.Pages))
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	opts.OCR = true
+	if _, err := ExtractContext(context.Background(), scanned, opts); !errors.Is(err, ErrOCRToolNotFound) {
+		t.Errorf("OCR without tools error = %v, want ErrOCRToolNotFound", err)
+	}
+}
+
+func TestExtractContextOCR(t *testing.T) {
+	dir := t.TempDir()
+	tsvPath := filepath.Join(dir, "page.tsv")
+	if err := os.WriteFile(tsvPath, []byte(sampleTesseractTSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// pdftoppm writes <prefix>.png with -singlefile; the prefix is the last argument.
+	fakeTool(t, "pdftoppm", `for last; do :; done; : > "$last.png"`)
+	fakeTool(t, "tesseract", `cat "`+tsvPath+`"`)
+
+	pdfPath := filepath.Join(dir, "statement.pdf")
+	writeTestPDF(t, pdfPath, "Owner Statement\f")
+
+	extraction, err := ExtractContext(context.Background(), pdfPath, ExtractOptions{Extractor: ExtractorNative, OCR: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := normalizeLayout(extraction.Text); got != "Owner Statement\nRent Income\n11/05/2025 1,200.00" {
+		t.Errorf("Text = %q", got)
+	}
+	if !regexp.MustCompile(`11/05/2025 {2,}1,200\.00`).MatchString(extraction.Text) {
+		t.Errorf("OCR text lost its column gap: %q", extraction.Text)
+	}
+
+	txs := []*parser.Transaction{
+		{Date: "2025-11-01", Payee: "Header", Line: 1},
+		{Date: "2025-11-05", Payee: "Tenant", Line: 3},
+		{Date: "2025-11-30", Directive: "balance", Line: 3},
+	}
+	if marked := FlagOCRTransactions(txs, extraction); marked != 1 {
+		t.Errorf("FlagOCRTransactions() = %d, want 1", marked)
+	}
+	if txs[0].Flag != "" || txs[1].Flag != parser.FlagReview || txs[1].Links[parser.ReviewKey] != "OCR page 2" {
+		t.Errorf("flags = %q, %q (%v)", txs[0].Flag, txs[1].Flag, txs[1].Links)
+	}
+}
@@ -0,0 +1,87 @@
+// internal/shared/order.go
+package shared
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// Order controls how entries are arranged in generated files.
+type Order int
+
+const (
+	// OrderDate sorts entries by date, with opening balances first and
+	// closing balances last on each day. Statement order breaks ties.
+	OrderDate Order = iota
+	// OrderStatement keeps entries in the order the parser produced them.
+	OrderStatement
+)
+
+// String returns the flag value for the order.
+func (o Order) String() string {
+	switch o {
+	case OrderDate:
+		return "date"
+	case OrderStatement:
+		return "statement"
+	default:
+		return fmt.Sprintf("Order(%d)", int(o))
+	}
+}
+
+// ParseOrder converts a flag value into an Order.
+func ParseOrder(value string) (Order, error) {
+	switch value {
+	case "", "date":
+		return OrderDate, nil
+	case "statement":
+		return OrderStatement, nil
+	default:
+		return 0, fmt.Errorf("unknown order %q (want date or statement)", value)
+	}
+}
+
+// Entry ranks within a single day.
+const (
+	rankOpeningBalance = iota
+	rankTransaction
+	rankClosingBalance
+)
+
+// SortTransactions returns a copy of txs arranged according to order.
+// Balance directives that precede every transaction in statement order are
+// treated as opening balances; all other balance directives are closing.
+// Notes sort with transactions but do not close the opening balances.
+func SortTransactions(txs []*parser.Transaction, order Order) []*parser.Transaction {
+	sorted := make([]*parser.Transaction, len(txs))
+	copy(sorted, txs)
+	if order == OrderStatement {
+		return sorted
+	}
+
+	ranks := make(map[*parser.Transaction]int, len(txs))
+	seenTransaction := false
+	for _, tx := range txs {
+		switch {
+		case tx.Directive == "note":
+			ranks[tx] = rankTransaction
+		case tx.Directive != "balance":
+			ranks[tx] = rankTransaction
+			seenTransaction = true
+		case seenTransaction:
+			ranks[tx] = rankClosingBalance
+		default:
+			ranks[tx] = rankOpeningBalance
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date < sorted[j].Date
+		}
+		return ranks[sorted[i]] < ranks[sorted[j]]
+	})
+	return sorted
+}
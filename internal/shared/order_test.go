@@ -0,0 +1,78 @@
+// internal/shared/order_test.go
+package shared
+
+import (
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestSortTransactions(t *testing.T) {
+	opening := &parser.Transaction{Date: "2025-11-01", Directive: "balance"}
+	distribution := &parser.Transaction{Date: "2025-11-06", Payee: "Distribution"}
+	rent := &parser.Transaction{Date: "2025-11-01", Payee: "Rent"}
+	feeA := &parser.Transaction{Date: "2025-11-03", Payee: "Fee A"}
+	feeB := &parser.Transaction{Date: "2025-11-03", Payee: "Fee B"}
+	closing := &parser.Transaction{Date: "2025-11-06", Directive: "balance"}
+	txs := []*parser.Transaction{opening, distribution, rent, feeA, feeB, closing}
+
+	tests := []struct {
+		name  string
+		order Order
+		want  []*parser.Transaction
+	}{
+		{
+			name:  "date order",
+			order: OrderDate,
+			want:  []*parser.Transaction{opening, rent, feeA, feeB, distribution, closing},
+		},
+		{
+			name:  "statement order",
+			order: OrderStatement,
+			want:  txs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SortTransactions(txs, tt.order)
+			if len(got) != len(tt.want) {
+				t.Fatalf("SortTransactions() returned %d entries, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("entry %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if txs[1] != distribution {
+		t.Errorf("SortTransactions() modified its input")
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Order
+		wantErr bool
+	}{
+		{value: "", want: OrderDate},
+		{value: "date", want: OrderDate},
+		{value: "statement", want: OrderStatement},
+		{value: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseOrder(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOrder(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParseOrder(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
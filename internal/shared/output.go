@@ -0,0 +1,56 @@
+// internal/shared/output.go
+package shared
+
+import "fmt"
+
+// OutputFormat selects the journal syntax used for generated files.
+type OutputFormat int
+
+const (
+	// OutputBeancount writes Beancount directives.
+	OutputBeancount OutputFormat = iota
+	// OutputLedger writes a ledger-cli journal.
+	OutputLedger
+	// OutputHledger writes an hledger journal.
+	OutputHledger
+)
+
+// String returns the flag value for the format.
+func (f OutputFormat) String() string {
+	switch f {
+	case OutputBeancount:
+		return "beancount"
+	case OutputLedger:
+		return "ledger"
+	case OutputHledger:
+		return "hledger"
+	default:
+		return fmt.Sprintf("OutputFormat(%d)", int(f))
+	}
+}
+
+// Extension returns the conventional file extension for the format.
+func (f OutputFormat) Extension() string {
+	switch f {
+	case OutputLedger:
+		return ".ledger"
+	case OutputHledger:
+		return ".journal"
+	default:
+		return ".bean"
+	}
+}
+
+// ParseOutputFormat converts a flag value into an OutputFormat.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch value {
+	case "", "beancount":
+		return OutputBeancount, nil
+	case "ledger":
+		return OutputLedger, nil
+	case "hledger":
+		return OutputHledger, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q (want beancount, ledger or hledger)", value)
+	}
+}
@@ -0,0 +1,37 @@
+// internal/shared/output_test.go
+package shared
+
+import "testing"
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    OutputFormat
+		wantExt string
+		wantErr bool
+	}{
+		{value: "", want: OutputBeancount, wantExt: ".bean"},
+		{value: "beancount", want: OutputBeancount, wantExt: ".bean"},
+		{value: "ledger", want: OutputLedger, wantExt: ".ledger"},
+		{value: "hledger", want: OutputHledger, wantExt: ".journal"},
+		{value: "gnucash", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseOutputFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOutputFormat(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseOutputFormat(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			if got.Extension() != tt.wantExt {
+				t.Errorf("%v.Extension() = %q, want %q", got, got.Extension(), tt.wantExt)
+			}
+		})
+	}
+}
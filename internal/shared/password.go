@@ -0,0 +1,195 @@
+// internal/shared/password.go
+package shared
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Secret is a string that is never printed: fmt verbs and slog render it as
+// "[redacted]" (or "" when empty). Convert with string() to use the value.
+type Secret string
+
+const redacted = "[redacted]"
+
+// String hides the value.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString hides the value from %#v.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// LogValue hides the value from slog.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// Passwords unlock an encrypted PDF. Either may be empty.
+type Passwords struct {
+	Owner Secret
+	User  Secret
+}
+
+// IsZero reports whether no password is set.
+func (p Passwords) IsZero() bool {
+	return p.Owner == "" && p.User == ""
+}
+
+var (
+	// ErrEncryptedPDF is returned when a PDF needs a password and none was given.
+	ErrEncryptedPDF = errors.New("PDF is encrypted and no password is configured")
+	// ErrWrongPassword is returned when none of the configured passwords open a PDF.
+	ErrWrongPassword = errors.New("PDF password is incorrect")
+)
+
+// Password environment variables. The institution-specific forms, e.g.
+// LGO_SPS_PDF_PASSWORD, take precedence over the generic ones.
+const (
+	PasswordEnv      = "LGO_PDF_PASSWORD"
+	OwnerPasswordEnv = "LGO_PDF_OWNER_PASSWORD"
+)
+
+// DotenvFiles are read from the working directory by LoadPasswords, later
+// files overriding earlier ones, matching the devenv dotenv setup.
+var DotenvFiles = []string{".env", ".env.local"}
+
+// DefaultSecretsDir returns the directory holding per-institution secrets
+// files: $XDG_CONFIG_HOME/lgo/secrets, usually ~/.config/lgo/secrets.
+func DefaultSecretsDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "lgo", "secrets")
+}
+
+// LoadPasswords looks up the PDF passwords for an institution. Sources are
+// consulted from lowest to highest precedence:
+//
+//  1. the secrets file <secretsDir>/<institution>.env
+//  2. .env and .env.local in the working directory
+//  3. the process environment
+//
+// All sources use the same KEY=value variables (see PasswordEnv). Missing
+// files are skipped; a secrets file readable by other users is reported
+// with a warning.
+func LoadPasswords(institution, secretsDir string) (Passwords, error) {
+	vars := map[string]string{}
+	var files []string
+	if secretsDir != "" {
+		files = append(files, filepath.Join(secretsDir, institution+".env"))
+	}
+	files = append(files, DotenvFiles...)
+	for i, path := range files {
+		loaded, err := readDotenv(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Passwords{}, err
+		}
+		if i == 0 && secretsDir != "" {
+			warnIfShared(path)
+		}
+		for k, v := range loaded {
+			vars[k] = v
+		}
+		slog.Debug("Loaded password settings", "path", path)
+	}
+	for _, generic := range []string{PasswordEnv, OwnerPasswordEnv} {
+		for _, key := range []string{generic, institutionKey(institution, generic)} {
+			if v, ok := os.LookupEnv(key); ok {
+				vars[key] = v
+			}
+		}
+	}
+
+	lookup := func(generic string) Secret {
+		if v := vars[institutionKey(institution, generic)]; v != "" {
+			return Secret(v)
+		}
+		return Secret(vars[generic])
+	}
+	passwords := Passwords{Owner: lookup(OwnerPasswordEnv), User: lookup(PasswordEnv)}
+	slog.Debug("Resolved PDF passwords", "institution", institution, "owner_set", passwords.Owner != "", "user_set", passwords.User != "")
+	return passwords, nil
+}
+
+// institutionKey returns the institution-specific form of a password
+// variable, e.g. LGO_SPS_PDF_PASSWORD for sps and LGO_PDF_PASSWORD.
+func institutionKey(institution, generic string) string {
+	return "LGO_" + envName(institution) + "_" + strings.TrimPrefix(generic, "LGO_")
+}
+
+// envName upper-cases an institution name for use in a variable name.
+func envName(institution string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, institution)
+}
+
+// readDotenv parses KEY=value lines, allowing blank lines, # comments, an
+// optional "export " prefix and single- or double-quoted values. Other
+// lines are skipped with a warning, so an unrelated .env in the working
+// directory does not stop lgo. Line contents are never logged.
+func readDotenv(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			slog.Warn("Skipping dotenv line that is not KEY=value", "path", path, "line", lineNum)
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+			value = value[1 : n-1]
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// warnIfShared warns when a secrets file is readable by group or others.
+func warnIfShared(path string) {
+	info, err := os.Stat(path)
+	if err == nil && info.Mode().Perm()&0o077 != 0 {
+		slog.Warn("Secrets file is accessible by other users; consider chmod 600", "path", path, "mode", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,203 @@
+// internal/shared/password_test.go
+package shared
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretRedacted(t *testing.T) {
+	passwords := Passwords{Owner: "owner-pass", User: "user-pass"}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	logger.Info("extracting", "passwords", passwords, "user", passwords.User)
+
+	outputs := []string{
+		fmt.Sprint(passwords),
+		fmt.Sprintf("%+v", ExtractOptions{Passwords: passwords}),
+		fmt.Sprintf("%#v", passwords),
+		fmt.Sprintf("%s", passwords.User),
+		logs.String(),
+	}
+	for _, out := range outputs {
+		if strings.Contains(out, "-pass") {
+			t.Errorf("password leaked in %q", out)
+		}
+	}
+	if got := Secret("").String(); got != "" {
+		t.Errorf("empty Secret String() = %q, want empty", got)
+	}
+}
+
+// clearPasswordEnv unsets the password variables for the test's duration.
+func clearPasswordEnv(t *testing.T, institution string) {
+	t.Helper()
+	for _, generic := range []string{PasswordEnv, OwnerPasswordEnv} {
+		for _, key := range []string{generic, institutionKey(institution, generic)} {
+			t.Setenv(key, "")
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestLoadPasswords(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets string
+		env     string
+		envLoc  string
+		vars    map[string]string
+		want    Passwords
+	}{
+		{name: "none"},
+		{
+			name:    "secrets file",
+			secrets: "LGO_PDF_PASSWORD=from-secrets\nLGO_PDF_OWNER_PASSWORD='owner secret'\n",
+			want:    Passwords{Owner: "owner secret", User: "from-secrets"},
+		},
+		{
+			name:    "dotenv overrides secrets file",
+			secrets: "LGO_PDF_PASSWORD=from-secrets\n",
+			env:     "# servicer statements\nexport LGO_PDF_PASSWORD=\"from-env\"\n",
+			want:    Passwords{User: "from-env"},
+		},
+		{
+			name:   "env.local overrides env",
+			env:    "LGO_PDF_PASSWORD=from-env\n",
+			envLoc: "LGO_PDF_PASSWORD=from-local # personal copy\n",
+			want:   Passwords{User: "from-local"},
+		},
+		{
+			name:   "environment overrides files",
+			envLoc: "LGO_PDF_PASSWORD=from-local\n",
+			vars:   map[string]string{"LGO_PDF_PASSWORD": "from-process"},
+			want:   Passwords{User: "from-process"},
+		},
+		{
+			name:    "institution variable wins",
+			secrets: "LGO_SPS_PDF_PASSWORD=sps-only\n",
+			vars:    map[string]string{"LGO_PDF_PASSWORD": "generic"},
+			want:    Passwords{User: "sps-only"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearPasswordEnv(t, "sps")
+			for k, v := range tt.vars {
+				t.Setenv(k, v)
+			}
+			workDir := t.TempDir()
+			secretsDir := t.TempDir()
+			t.Chdir(workDir)
+			files := map[string]string{
+				filepath.Join(secretsDir, "sps.env"): tt.secrets,
+				".env":                               tt.env,
+				".env.local":                         tt.envLoc,
+			}
+			for path, content := range files {
+				if content == "" {
+					continue
+				}
+				if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadPasswords("sps", secretsDir)
+			if err != nil {
+				t.Fatalf("LoadPasswords() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("LoadPasswords() = {Owner: %q, User: %q}, want {Owner: %q, User: %q}",
+					string(got.Owner), string(got.User), string(tt.want.Owner), string(tt.want.User))
+			}
+		})
+	}
+}
+
+func TestLoadPasswordsMalformedDotenv(t *testing.T) {
+	clearPasswordEnv(t, "sps")
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(".env", []byte("LGO_PDF_PASSWORD=ok\nhunter2\n=nokey\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	got, err := LoadPasswords("sps", "")
+	if err != nil {
+		t.Fatalf("LoadPasswords() error = %v, want malformed lines skipped", err)
+	}
+	if got.User != "ok" {
+		t.Errorf("LoadPasswords() user password = %q, want the well-formed line's value", string(got.User))
+	}
+	if !strings.Contains(logs.String(), "line=2") || !strings.Contains(logs.String(), "line=3") || strings.Contains(logs.String(), "hunter2") {
+		t.Errorf("logs = %q, want warnings for lines 2 and 3 without their content", logs.String())
+	}
+}
+
+func TestExtractNativeEncrypted(t *testing.T) {
+	pdfPath := filepath.Join(t.TempDir(), "locked.pdf")
+	writeEncryptedTestPDF(t, pdfPath, "Mortgage Statement", Passwords{Owner: "owner-pw", User: "user-pw"})
+
+	tests := []struct {
+		name      string
+		passwords Passwords
+		wantErr   error
+	}{
+		{name: "no password", wantErr: ErrEncryptedPDF},
+		{name: "wrong password", passwords: Passwords{User: "guess-pw"}, wantErr: ErrWrongPassword},
+		{name: "user password", passwords: Passwords{User: "user-pw"}},
+		{name: "user password in owner slot", passwords: Passwords{Owner: "user-pw"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			defer slog.SetDefault(slog.Default())
+			slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+			got, err := ExtractTextContext(context.Background(), pdfPath, ExtractOptions{Extractor: ExtractorNative, Passwords: tt.passwords})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtractTextContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != "Mortgage Statement" {
+				t.Errorf("ExtractTextContext() = %q, want %q", got, "Mortgage Statement")
+			}
+			if strings.Contains(logs.String(), "-pw") || (err != nil && strings.Contains(err.Error(), "-pw")) {
+				t.Errorf("password leaked in logs or error:\n%s\n%v", logs.String(), err)
+			}
+		})
+	}
+}
+
+func TestExtractPdftotextPasswordFlags(t *testing.T) {
+	argsPath := filepath.Join(t.TempDir(), "args")
+	fakeTool(t, "pdftotext", `printf '%s\n' "$@" > "`+argsPath+`"
+printf '<doc><page width="612" height="792"><word xMin="36" yMin="38" xMax="90" yMax="48">Statement</word></page></doc>'`)
+
+	if _, err := ExtractTextWithOptions("locked.pdf", ExtractOptions{
+		Extractor: ExtractorPdftotext,
+		Passwords: Passwords{Owner: "owner-pw", User: "user-pw"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "-opw\nowner-pw\n-upw\nuser-pw\n-bbox-layout\nlocked.pdf\n-\n"
+	if string(got) != want {
+		t.Errorf("pdftotext args = %q, want %q", got, want)
+	}
+}
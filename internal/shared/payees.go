@@ -0,0 +1,169 @@
+// internal/shared/payees.go
+package shared
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// PayeeAlias maps the payees institutions print to one canonical name.
+type PayeeAlias struct {
+	Name string `json:"name"`
+	// Raw lists payees matched exactly, ignoring case and spacing.
+	Raw []string `json:"raw,omitempty"`
+	// Patterns lists regular expressions matched against the whole payee.
+	Patterns []string `json:"patterns,omitempty"`
+
+	patternRes []*regexp.Regexp
+}
+
+// compile checks the alias and compiles its patterns.
+func (a *PayeeAlias) compile() error {
+	if a.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(a.Raw) == 0 && len(a.Patterns) == 0 {
+		return fmt.Errorf("%q needs raw payees or patterns", a.Name)
+	}
+	a.patternRes = nil
+	for _, pattern := range a.Patterns {
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("%q: pattern: %w", a.Name, err)
+		}
+		a.patternRes = append(a.patternRes, re)
+	}
+	return nil
+}
+
+// matches reports whether payee is one of the alias's raw payees or
+// matches one of its patterns.
+func (a *PayeeAlias) matches(payee string) bool {
+	for _, raw := range a.Raw {
+		if strings.EqualFold(cleanPayee(raw), payee) {
+			return true
+		}
+	}
+	for _, re := range a.patternRes {
+		if re.MatchString(payee) {
+			return true
+		}
+	}
+	return false
+}
+
+// payeesFile is the layout of a payee alias file.
+type payeesFile struct {
+	Payees []PayeeAlias `json:"payees"`
+}
+
+// DefaultPayeesPath returns the payee alias file read by default:
+// $XDG_CONFIG_HOME/lgo/payees.json, usually ~/.config/lgo/payees.json.
+func DefaultPayeesPath() string {
+	return configPath("payees.json")
+}
+
+// LoadPayees reads the aliases in a JSON file of the form
+// {"payees": [...]}. A missing file has no aliases.
+func LoadPayees(path string) ([]PayeeAlias, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Debug("No payees file", "path", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var file payeesFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i := range file.Payees {
+		if err := file.Payees[i].compile(); err != nil {
+			return nil, fmt.Errorf("%s: payee %d: %w", path, i+1, err)
+		}
+	}
+	slog.Debug("Loaded payee aliases", "path", path, "count", len(file.Payees))
+	return file.Payees, nil
+}
+
+// CanonicalPayee returns the name of the first alias matching payee, and
+// whether one did.
+func CanonicalPayee(aliases []PayeeAlias, payee string) (string, bool) {
+	payee = cleanPayee(payee)
+	for i := range aliases {
+		if aliases[i].matches(payee) {
+			return aliases[i].Name, true
+		}
+	}
+	return "", false
+}
+
+// NormalizePayees replaces each transaction payee an alias matches with the
+// alias's name, returning how many changed.
+func NormalizePayees(txs []*parser.Transaction, aliases []PayeeAlias) int {
+	changed := 0
+	for _, tx := range txs {
+		if tx.Payee == "" {
+			continue
+		}
+		if name, ok := CanonicalPayee(aliases, tx.Payee); ok && name != tx.Payee {
+			tx.Payee = name
+			changed++
+		}
+	}
+	slog.Debug("Normalized payees", "aliases", len(aliases), "changed", changed)
+	return changed
+}
+
+// RawPayee is a payee in a ledger that is not a canonical name.
+type RawPayee struct {
+	Payee string
+	// Count is how many transactions use it.
+	Count int
+	// Canonical is the name an alias would give it, or "" when no alias
+	// matches it yet.
+	Canonical string
+}
+
+// UnnormalizedPayees lists the distinct payees of the history's
+// transactions that are not an alias's name, most used first.
+func UnnormalizedPayees(history *History, aliases []PayeeAlias) []RawPayee {
+	names := map[string]bool{}
+	for _, alias := range aliases {
+		names[alias.Name] = true
+	}
+	counts := map[string]int{}
+	for _, entry := range history.Entries {
+		if entry.Payee != "" && !names[entry.Payee] {
+			counts[entry.Payee]++
+		}
+	}
+	var payees []RawPayee
+	for payee, count := range counts {
+		canonical, _ := CanonicalPayee(aliases, payee)
+		payees = append(payees, RawPayee{Payee: payee, Count: count, Canonical: canonical})
+	}
+	slices.SortFunc(payees, func(a, b RawPayee) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Payee, b.Payee))
+	})
+	return payees
+}
+
+// cleanPayee trims a payee and collapses its runs of spaces.
+func cleanPayee(payee string) string {
+	return strings.Join(strings.Fields(payee), " ")
+}
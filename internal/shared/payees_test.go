@@ -0,0 +1,105 @@
+// internal/shared/payees_test.go
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestNormalizePayees(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payees.json")
+	content := `{"payees": [
+		{"name": "CloverLeaf", "raw": ["CloverLeaf Property Management", "CloverLeaf PM"]},
+		{"name": "Green Yards", "patterns": ["(?i)green yards( by .*)?"]},
+		{"name": "Handyman Co", "raw": ["Contractor"]}
+	]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	aliases, err := LoadPayees(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		payee string
+		want  string
+	}{
+		{payee: "CloverLeaf Property Management", want: "CloverLeaf"},
+		{payee: "cloverleaf  pm ", want: "CloverLeaf"},
+		{payee: "Green Yards by J. Smith", want: "Green Yards"},
+		{payee: "GREEN YARDS", want: "Green Yards"},
+		{payee: "Green Yards Supply", want: "Green Yards Supply"},
+		{payee: "Contractor", want: "Handyman Co"},
+		{payee: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.payee, func(t *testing.T) {
+			tx := &parser.Transaction{Payee: tt.payee}
+			NormalizePayees([]*parser.Transaction{tx}, aliases)
+			if tx.Payee != tt.want {
+				t.Errorf("payee = %q, want %q", tx.Payee, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPayees(t *testing.T) {
+	aliases, err := LoadPayees(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || aliases != nil {
+		t.Errorf("LoadPayees(missing) = %v, %v; want no aliases", aliases, err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no name", content: `{"payees": [{"raw": ["Contractor"]}]}`, wantErr: "payee 1: name is required"},
+		{name: "nothing to match", content: `{"payees": [{"name": "Handyman Co"}]}`, wantErr: `"Handyman Co" needs raw payees or patterns`},
+		{name: "bad pattern", content: `{"payees": [{"name": "Handyman Co", "patterns": ["("]}]}`, wantErr: "pattern:"},
+		{name: "unknown field", content: `{"payees": [{"name": "Handyman Co", "regex": "x"}]}`, wantErr: `unknown field "regex"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "payees.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadPayees(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadPayees() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnnormalizedPayees(t *testing.T) {
+	aliases := []PayeeAlias{{Name: "CloverLeaf", Raw: []string{"CloverLeaf Property Management"}}}
+	for i := range aliases {
+		if err := aliases[i].compile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history := &History{Entries: []HistoryEntry{
+		{Payee: "CloverLeaf"},
+		{Payee: "Contractor"},
+		{Payee: "CloverLeaf Property Management"},
+		{Narration: "Owner draw"},
+		{Payee: "Contractor"},
+		{Payee: "Acme"},
+	}}
+	want := []RawPayee{
+		{Payee: "Contractor", Count: 2},
+		{Payee: "Acme", Count: 1},
+		{Payee: "CloverLeaf Property Management", Count: 1, Canonical: "CloverLeaf"},
+	}
+	if got := UnnormalizedPayees(history, aliases); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnnormalizedPayees() = %+v, want %+v", got, want)
+	}
+}
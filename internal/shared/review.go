@@ -0,0 +1,89 @@
+// internal/shared/review.go
+package shared
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// ReviewEntry is a transaction flagged for review in a ledger file.
+type ReviewEntry struct {
+	Path string
+	// Line is the 1-based line of the transaction's first line.
+	Line int
+	Date string
+	// Description is the rest of the first line after the flag: payee and
+	// narration as written in the file.
+	Description string
+	// Reason is the review metadata, or "" when the entry has none.
+	Reason string
+}
+
+var (
+	// flaggedRe matches the first line of a "!" transaction in Beancount,
+	// ledger and hledger files.
+	flaggedRe = regexp.MustCompile(`^(\d{4}[-/]\d{2}[-/]\d{2})\s+!\s*(.*)$`)
+	// reviewMetaRe matches review metadata: Beancount's `review: "..."`
+	// and the ledger comment form `; review: ...`.
+	reviewMetaRe = regexp.MustCompile(`^\s+(?:;\s*)?` + parser.ReviewKey + `:\s*(.*)$`)
+	includeRe    = regexp.MustCompile(`^!?include\s+(.+)$`)
+)
+
+// FindReviews lists the transactions flagged for review in the ledger file
+// at path and the files it includes, in file order. Include patterns are
+// resolved relative to the including file and may be globs; a file
+// included twice is read once.
+func FindReviews(path string) ([]ReviewEntry, error) {
+	var entries []ReviewEntry
+	// current is the flagged entry whose metadata is being read.
+	var current *ReviewEntry
+	err := walkLedger(path, map[string]bool{}, func(path string, lineNum int, line string) {
+		if current != nil {
+			if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+				if m := reviewMetaRe.FindStringSubmatch(line); m != nil {
+					current.Reason = unquote(strings.TrimSpace(m[1]))
+				}
+				return
+			}
+			entries = append(entries, *current)
+			current = nil
+		}
+		if m := flaggedRe.FindStringSubmatch(line); m != nil {
+			current = &ReviewEntry{Path: path, Line: lineNum, Date: m[1], Description: strings.TrimSpace(m[2])}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		entries = append(entries, *current)
+	}
+	slog.Debug("Found entries flagged for review", "path", path, "count", len(entries))
+	return entries, nil
+}
+
+// RebookSuspense moves the postings parsers booked to
+// parser.SuspenseAccount in flagged entries to account, returning how many
+// were moved.
+func RebookSuspense(txs []*parser.Transaction, account string) int {
+	if account == parser.SuspenseAccount {
+		return 0
+	}
+	moved := 0
+	for _, tx := range txs {
+		if tx.Flag != parser.FlagReview {
+			continue
+		}
+		for i := range tx.Postings {
+			if tx.Postings[i].Account == parser.SuspenseAccount {
+				tx.Postings[i].Account = account
+				moved++
+			}
+		}
+	}
+	slog.Debug("Rebooked suspense postings", "account", account, "postings", moved)
+	return moved
+}
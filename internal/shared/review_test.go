@@ -0,0 +1,124 @@
+// internal/shared/review_test.go
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestFindReviews(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.bean": `option "title" "Rental Property Ledger"
+include "2025/*.bean"
+include "2025/cloverleaf.bean"
+
+2025-01-05 * "Tenant" "Rent"
+  Income:Rent:206-Hoover-Ave    -1150.00 USD
+  Assets:Property-Management:SheerValue-PM
+
+2025-01-06 ! "Bank" "Wire fee"
+  Expenses:Unidentified    15.00 USD
+  Assets:Property-Management:SheerValue-PM
+`,
+		"2025/cloverleaf.bean": `2025-11-20 ! "Pest Control - Quarterly" "Memo: Pest Control - Quarterly" #imported
+  comments: ""
+  review: "no account rule matches the description"
+  Assets:Property-Management:CloverLeaf-PM     -95.00 USD
+  Expenses:Unidentified                         95.00 USD
+`,
+		"2025/ocr.journal": `2025/11/21 ! Memo: Lawn care
+  ; Payee: Contractor
+  ; review: OCR page 2
+  Assets:Property-Management:CloverLeaf-PM     -40.00 USD
+  Expenses:Cleaning---Maintenance:206-Hoover-Ave 40.00 USD`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// ocr.journal is not matched by the *.bean include; read it directly.
+	var got []ReviewEntry
+	for _, name := range []string{"main.bean", "2025/ocr.journal"} {
+		entries, err := FindReviews(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, entries...)
+	}
+	for i := range got {
+		got[i].Path = strings.TrimPrefix(filepath.ToSlash(strings.TrimPrefix(got[i].Path, dir)), "/")
+	}
+
+	want := []ReviewEntry{
+		{Path: "2025/cloverleaf.bean", Line: 1, Date: "2025-11-20", Description: `"Pest Control - Quarterly" "Memo: Pest Control - Quarterly" #imported`, Reason: "no account rule matches the description"},
+		{Path: "main.bean", Line: 9, Date: "2025-01-06", Description: `"Bank" "Wire fee"`},
+		{Path: "2025/ocr.journal", Line: 1, Date: "2025/11/21", Description: "Memo: Lawn care", Reason: "OCR page 2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindReviews() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := FindReviews(filepath.Join(dir, "missing.bean")); err == nil {
+		t.Errorf("FindReviews() on a missing file returned no error")
+	}
+	broken := filepath.Join(dir, "broken.bean")
+	if err := os.WriteFile(broken, []byte("include \"nowhere/*.bean\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindReviews(broken); err == nil || !strings.Contains(err.Error(), "broken.bean:1") {
+		t.Errorf("FindReviews() with a dangling include error = %v, want the include's line", err)
+	}
+}
+
+func TestRebookSuspense(t *testing.T) {
+	posting := func(account, value string) parser.Posting {
+		return parser.Posting{Account: account, Amount: parser.Amount{Value: value, Currency: "USD"}}
+	}
+	tests := []struct {
+		name    string
+		account string
+		tx      *parser.Transaction
+		want    string
+	}{
+		{
+			name:    "flagged entry",
+			account: "Expenses:Suspense",
+			tx:      &parser.Transaction{Flag: parser.FlagReview, Postings: []parser.Posting{posting("Assets:PM", "-95.00"), posting(parser.SuspenseAccount, "95.00")}},
+			want:    "Expenses:Suspense",
+		},
+		{
+			name:    "cleared entry kept",
+			account: "Expenses:Suspense",
+			tx:      &parser.Transaction{Postings: []parser.Posting{posting("Assets:PM", "-95.00"), posting(parser.SuspenseAccount, "95.00")}},
+			want:    parser.SuspenseAccount,
+		},
+		{
+			name:    "default account",
+			account: parser.SuspenseAccount,
+			tx:      &parser.Transaction{Flag: parser.FlagReview, Postings: []parser.Posting{posting("Assets:PM", "-95.00"), posting(parser.SuspenseAccount, "95.00")}},
+			want:    parser.SuspenseAccount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RebookSuspense([]*parser.Transaction{tt.tx}, tt.account)
+			if got := tt.tx.Postings[1].Account; got != tt.want {
+				t.Errorf("suspense posting account = %q, want %q", got, tt.want)
+			}
+			if got := tt.tx.Postings[0].Account; got != "Assets:PM" {
+				t.Errorf("other posting account = %q, want Assets:PM", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// internal/shared/rules.go
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// Matcher selects transactions by payee and narration. Payee is compared
+// case-insensitively and Narration is a regular expression; an empty field
+// matches any transaction.
+type Matcher struct {
+	Payee     string `json:"payee,omitempty"`
+	Narration string `json:"narration,omitempty"`
+
+	narrationRe *regexp.Regexp
+}
+
+// compile compiles the narration pattern.
+func (m *Matcher) compile() error {
+	if m.Narration == "" {
+		return nil
+	}
+	re, err := regexp.Compile(m.Narration)
+	if err != nil {
+		return fmt.Errorf("narration: %w", err)
+	}
+	m.narrationRe = re
+	return nil
+}
+
+// Matches reports whether tx matches m.
+func (m *Matcher) Matches(tx *parser.Transaction) bool {
+	switch {
+	case m.Payee != "" && !strings.EqualFold(tx.Payee, m.Payee):
+		return false
+	case m.narrationRe != nil && !m.narrationRe.MatchString(tx.Narration):
+		return false
+	}
+	return true
+}
+
+// Rule books uncategorized transactions it matches to Account.
+type Rule struct {
+	Matcher
+	Account string `json:"account"`
+}
+
+// NewRule returns a compiled rule.
+func NewRule(payee, narration, account string) (Rule, error) {
+	rule := Rule{Matcher: Matcher{Payee: payee, Narration: narration}, Account: account}
+	if err := rule.compile(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// compile checks the rule and compiles its Matcher.
+func (r *Rule) compile() error {
+	if r.Account == "" {
+		return fmt.Errorf("account is required")
+	}
+	if r.Payee == "" && r.Narration == "" {
+		return fmt.Errorf("payee or narration is required")
+	}
+	return r.Matcher.compile()
+}
+
+// rulesFile is the layout of a rules file.
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// DefaultRulesPath returns the rules file read by default:
+// $XDG_CONFIG_HOME/lgo/rules.json, usually ~/.config/lgo/rules.json.
+func DefaultRulesPath() string {
+	return configPath("rules.json")
+}
+
+// configPath returns the path of the named file in lgo's configuration
+// directory, or "" when the user has none.
+func configPath(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "lgo", name)
+}
+
+// LoadRules reads the rules in a JSON file of the form {"rules": [...]}.
+// A missing file has no rules.
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Debug("No rules file", "path", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var file rulesFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i := range file.Rules {
+		if err := file.Rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+	}
+	slog.Debug("Loaded rules", "path", path, "count", len(file.Rules))
+	return file.Rules, nil
+}
+
+// SaveRules writes rules to path, creating its directory, replacing the
+// file only once the new contents are written.
+func SaveRules(path string, rules []Rule) error {
+	data, err := json.MarshalIndent(rulesFile{Rules: rules}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	slog.Debug("Saved rules", "path", path, "count", len(rules))
+	return nil
+}
+
+// Uncategorized reports whether a parser booked tx to the suspense account
+// because no account rule matched it.
+func Uncategorized(tx *parser.Transaction) bool {
+	if tx.Flag != parser.FlagReview {
+		return false
+	}
+	for _, p := range tx.Postings {
+		if p.Account == parser.SuspenseAccount {
+			return true
+		}
+	}
+	return false
+}
+
+// Categorize moves tx's suspense postings to account. The review flag is
+// cleared unless the entry was flagged for another reason, like OCR.
+func Categorize(tx *parser.Transaction, account string) {
+	for i := range tx.Postings {
+		if tx.Postings[i].Account == parser.SuspenseAccount {
+			tx.Postings[i].Account = account
+		}
+	}
+	if tx.Links[parser.ReviewKey] == parser.UnmatchedReason {
+		delete(tx.Links, parser.ReviewKey)
+		tx.Flag = ""
+	}
+}
+
+// ApplyRules categorizes each uncategorized transaction with the first rule
+// that matches it, returning how many were categorized.
+func ApplyRules(txs []*parser.Transaction, rules []Rule) int {
+	applied := 0
+	for _, tx := range txs {
+		if !Uncategorized(tx) {
+			continue
+		}
+		for i := range rules {
+			if rules[i].Matches(tx) {
+				Categorize(tx, rules[i].Account)
+				applied++
+				break
+			}
+		}
+	}
+	slog.Debug("Applied categorization rules", "rules", len(rules), "categorized", applied)
+	return applied
+}
@@ -0,0 +1,115 @@
+// internal/shared/rules_test.go
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestApplyRules(t *testing.T) {
+	unmatched := func(payee, narration, reason string) *parser.Transaction {
+		tx := &parser.Transaction{Payee: payee, Narration: narration, Postings: []parser.Posting{
+			{Account: "Assets:PM", Amount: parser.Amount{Value: "-40.00", Currency: "USD"}},
+			{Account: parser.SuspenseAccount, Amount: parser.Amount{Value: "40.00", Currency: "USD"}},
+		}}
+		tx.MarkForReview(reason)
+		return tx
+	}
+	rules := []Rule{
+		{Matcher: Matcher{Payee: "green yards"}, Account: "Expenses:Landscaping"},
+		{Matcher: Matcher{Narration: "(?i)pest"}, Account: "Expenses:Pest-Control"},
+	}
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		tx       *parser.Transaction
+		want     string
+		wantFlag string
+	}{
+		{name: "payee ignores case", tx: unmatched("Green Yards", "Memo: Mowing", parser.UnmatchedReason), want: "Expenses:Landscaping"},
+		{name: "narration pattern", tx: unmatched("Vendor", "Memo: Pest Control", parser.UnmatchedReason), want: "Expenses:Pest-Control"},
+		{name: "no rule matches", tx: unmatched("Vendor", "Memo: Roof", parser.UnmatchedReason), want: parser.SuspenseAccount, wantFlag: parser.FlagReview},
+		{name: "other review reason stays flagged", tx: unmatched("Green Yards", "Memo: Mowing", "low OCR confidence"), want: "Expenses:Landscaping", wantFlag: parser.FlagReview},
+		{
+			name: "already categorized",
+			tx:   &parser.Transaction{Payee: "Green Yards", Postings: []parser.Posting{{Account: "Expenses:Other"}, {Account: "Assets:PM"}}},
+			want: "Assets:PM",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ApplyRules([]*parser.Transaction{tt.tx}, rules)
+			if got := tt.tx.Postings[1].Account; got != tt.want {
+				t.Errorf("account = %q, want %q", got, tt.want)
+			}
+			if tt.tx.Flag != tt.wantFlag {
+				t.Errorf("flag = %q, want %q", tt.tx.Flag, tt.wantFlag)
+			}
+			if _, ok := tt.tx.Links[parser.ReviewKey]; ok != (tt.wantFlag != "") {
+				t.Errorf("review metadata = %q", tt.tx.Links[parser.ReviewKey])
+			}
+		})
+	}
+}
+
+func TestSaveRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lgo", "rules.json")
+	rules, err := LoadRules(path)
+	if err != nil || rules != nil {
+		t.Fatalf("LoadRules(missing) = %v, %v; want no rules", rules, err)
+	}
+
+	rule, err := NewRule("", "(?i)mowing", "Expenses:Landscaping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rule{{Matcher: Matcher{Payee: "Green Yards"}, Account: "Expenses:Landscaping"}, rule}
+	if err := SaveRules(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadRules() = %+v, want %+v", got, want)
+	}
+	if _, err := os.Stat(path + ".tmp"); err == nil {
+		t.Errorf("temporary file left behind")
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no account", content: `{"rules": [{"payee": "Bank"}]}`, wantErr: "rule 1: account is required"},
+		{name: "no matcher", content: `{"rules": [{"account": "Expenses:Other"}]}`, wantErr: "rule 1: payee or narration is required"},
+		{name: "bad narration", content: `{"rules": [{"narration": "(", "account": "Expenses:Other"}]}`, wantErr: "narration:"},
+		{name: "unknown field", content: `{"rules": [{"payee": "Bank", "acount": "Expenses:Other"}]}`, wantErr: `unknown field "acount"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadRules(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadRules() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
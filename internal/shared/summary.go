@@ -0,0 +1,187 @@
+// internal/shared/summary.go
+package shared
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// SummaryCheck controls what happens when summary totals disagree with
+// the imported entries.
+type SummaryCheck int
+
+const (
+	// SummaryFail rejects the statement.
+	SummaryFail SummaryCheck = iota
+	// SummaryWarn logs each mismatch and keeps the entries.
+	SummaryWarn
+	// SummaryOff skips the check.
+	SummaryOff
+)
+
+// String returns the flag value for the check.
+func (c SummaryCheck) String() string {
+	switch c {
+	case SummaryFail:
+		return "fail"
+	case SummaryWarn:
+		return "warn"
+	case SummaryOff:
+		return "off"
+	default:
+		return fmt.Sprintf("SummaryCheck(%d)", int(c))
+	}
+}
+
+// ParseSummaryCheck converts a flag value into a SummaryCheck.
+func ParseSummaryCheck(value string) (SummaryCheck, error) {
+	switch value {
+	case "", "fail":
+		return SummaryFail, nil
+	case "warn":
+		return SummaryWarn, nil
+	case "off":
+		return SummaryOff, nil
+	default:
+		return 0, fmt.Errorf("unknown summary check %q (want fail, warn or off)", value)
+	}
+}
+
+// SummaryMismatch is a summary total that disagrees with the entries.
+type SummaryMismatch struct {
+	Total parser.SummaryTotal
+	// Got is the sum of the postings the total covers.
+	Got string
+	// Suspense is the sum of the postings to parser.SuspenseAccount, on
+	// the side of the difference, when it is large enough to account for
+	// it. Such a mismatch should go away once the suspense entries are
+	// categorized; it is "" otherwise.
+	Suspense string
+}
+
+// SummaryMismatchError reports summary totals that disagree with the sum of
+// the imported entries.
+type SummaryMismatchError struct {
+	Mismatches []SummaryMismatch
+}
+
+func (e *SummaryMismatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d summary total(s) disagree with imported entries:", len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		scope := "all properties"
+		if m.Total.Property != "" {
+			scope = m.Total.Property
+		}
+		fmt.Fprintf(&b, "\n  line %d: %s (%s, %s): statement %s, entries %s", m.Total.Line, m.Total.Label, m.Total.Account, scope, m.Total.Amount, m.Got)
+		if m.Suspense != "" {
+			fmt.Fprintf(&b, ", suspense %s", m.Suspense)
+		}
+	}
+	return b.String()
+}
+
+// CheckSummary compares each summary total that names an account against
+// the sum of the postings to that account and its subaccounts, limited to
+// the total's property when it has one. Postings to parser.SuspenseAccount
+// belong to no total, since the parser could not tell where they go; a
+// mismatch they could explain has Suspense set. It returns a
+// *SummaryMismatchError listing the totals that disagree, or nil.
+func CheckSummary(txs []*parser.Transaction, totals []parser.SummaryTotal) error {
+	// Suspense debits and credits are summed apart: an expense short of its
+	// total can only be explained by debits, and income only by credits.
+	var suspenseDebits, suspenseCredits int64
+	for _, tx := range txs {
+		for _, p := range tx.Postings {
+			if p.Account != parser.SuspenseAccount {
+				continue
+			}
+			amount, err := cents(p.Amount.Value)
+			if err != nil {
+				return fmt.Errorf("posting to %s: %w", p.Account, err)
+			}
+			if amount > 0 {
+				suspenseDebits += amount
+			} else {
+				suspenseCredits += amount
+			}
+		}
+	}
+
+	var mismatches []SummaryMismatch
+	for _, total := range totals {
+		if total.Account == "" {
+			continue
+		}
+		want, err := cents(total.Amount)
+		if err != nil {
+			return fmt.Errorf("summary line %d: %w", total.Line, err)
+		}
+		var got int64
+		for _, tx := range txs {
+			for _, p := range tx.Postings {
+				if !coversAccount(total, p.Account) {
+					continue
+				}
+				amount, err := cents(p.Amount.Value)
+				if err != nil {
+					return fmt.Errorf("posting to %s: %w", p.Account, err)
+				}
+				got += amount
+			}
+		}
+		if got == want {
+			continue
+		}
+		mismatch := SummaryMismatch{Total: total, Got: formatCents(got)}
+		switch diff := want - got; {
+		case diff > 0 && diff <= suspenseDebits:
+			mismatch.Suspense = formatCents(suspenseDebits)
+		case diff < 0 && diff >= suspenseCredits:
+			mismatch.Suspense = formatCents(suspenseCredits)
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	slog.Debug("Checked summary totals", "totals", len(totals), "mismatches", len(mismatches))
+	if len(mismatches) > 0 {
+		return &SummaryMismatchError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+// coversAccount reports whether account is one the total sums.
+func coversAccount(total parser.SummaryTotal, account string) bool {
+	if account == parser.SuspenseAccount {
+		return false
+	}
+	if account != total.Account && !strings.HasPrefix(account, total.Account+":") {
+		return false
+	}
+	return total.Property == "" || strings.HasSuffix(account, ":"+total.Property)
+}
+
+// cents converts a decimal amount such as -1053.1 to a count of cents.
+func cents(value string) (int64, error) {
+	whole, frac, _ := strings.Cut(value, ".")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("amount %q has more than two decimal places", value)
+	}
+	n, err := strconv.ParseInt(whole+(frac + "00")[:2], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return n, nil
+}
+
+// formatCents renders cents as a decimal amount with two places.
+func formatCents(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, n/100, n%100)
+}
@@ -0,0 +1,78 @@
+// internal/shared/summary_test.go
+package shared
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+func TestCheckSummary(t *testing.T) {
+	posting := func(account, value string) parser.Posting {
+		return parser.Posting{Account: account, Amount: parser.Amount{Value: value, Currency: "USD"}}
+	}
+	txs := []*parser.Transaction{
+		{Postings: []parser.Posting{posting("Income:Rent:206-Hoover-Ave", "-1150.00"), posting("Assets:PM", "1150.00")}},
+		{Postings: []parser.Posting{posting("Income:Late-Rent-Fee:206-Hoover-Ave", "-628.50"), posting("Assets:PM", "628.50")}},
+		{Postings: []parser.Posting{posting("Income:Rent:2943-Butterfly-Palm", "-1550.00"), posting("Assets:PM", "1550.00")}},
+		{Postings: []parser.Posting{posting("Assets:PM", "-103.50"), posting("Expenses:Management-Fees:206-Hoover-Ave", "103.50")}},
+		{Postings: []parser.Posting{posting("Assets:PM", "-2.00"), posting(parser.SuspenseAccount, "2.00")}},
+		{Postings: []parser.Posting{posting(parser.SuspenseAccount, "-30.00"), posting("Assets:PM", "30.00")}},
+		{Directive: "balance", BalanceAccount: "Assets:PM", BalanceAmount: parser.Amount{Value: "9.99", Currency: "USD"}},
+	}
+
+	tests := []struct {
+		name  string
+		total parser.SummaryTotal
+		want  string // sum reported in the mismatch, "" for a match
+		// suspense reported in the mismatch
+		suspense string
+	}{
+		{name: "all income", total: parser.SummaryTotal{Account: "Income", Amount: "-3328.50"}},
+		{name: "property income", total: parser.SummaryTotal{Account: "Income", Property: "206-Hoover-Ave", Amount: "-1778.5"}},
+		{name: "one account", total: parser.SummaryTotal{Account: "Income:Rent:2943-Butterfly-Palm", Amount: "-1550.00"}},
+		{name: "no postings", total: parser.SummaryTotal{Account: "Income:Pet-Fee", Property: "206-Hoover-Ave", Amount: "0.00"}},
+		{name: "not checked", total: parser.SummaryTotal{Label: "Reserve", Amount: "900.00"}},
+		{name: "prefix is not a parent", total: parser.SummaryTotal{Account: "Income:Rent:2943", Amount: "0.00"}},
+		{name: "expenses differ", total: parser.SummaryTotal{Account: "Expenses", Amount: "107.50"}, want: "103.50"},
+		{name: "property differs", total: parser.SummaryTotal{Account: "Income", Property: "2943-Butterfly-Palm", Amount: "-1600.00"}, want: "-1550.00"},
+		{name: "suspense debits explain expenses", total: parser.SummaryTotal{Account: "Expenses", Amount: "105.50"}, want: "103.50", suspense: "2.00"},
+		{name: "suspense credits explain income", total: parser.SummaryTotal{Account: "Income", Property: "206-Hoover-Ave", Amount: "-1800.00"}, want: "-1778.50", suspense: "-30.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSummary(txs, []parser.SummaryTotal{tt.total})
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("CheckSummary() error = %v, want nil", err)
+				}
+				return
+			}
+			var mismatch *SummaryMismatchError
+			if !errors.As(err, &mismatch) || len(mismatch.Mismatches) != 1 || mismatch.Mismatches[0].Got != tt.want || mismatch.Mismatches[0].Suspense != tt.suspense {
+				t.Errorf("CheckSummary() error = %v, want entries sum %s and suspense %q", err, tt.want, tt.suspense)
+			}
+		})
+	}
+}
+
+func TestParseSummaryCheck(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SummaryCheck
+		wantErr bool
+	}{
+		{in: "", want: SummaryFail},
+		{in: "fail", want: SummaryFail},
+		{in: "warn", want: SummaryWarn},
+		{in: "off", want: SummaryOff},
+		{in: "skip", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSummaryCheck(tt.in)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseSummaryCheck(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -4,11 +4,16 @@ package shared
 import (
 	"fmt"
 	"log/slog"
+	"math"
 	"strconv"
 
 	"github.com/jason-riddle/ledger-go/internal/parser"
 )
 
+// balanceTolerance absorbs the float error in summing decimal amounts, such
+// as 12.51 + 12.50 - 25.01, while catching any imbalance of a cent or more.
+const balanceTolerance = 0.005
+
 // ValidateTransactions checks that transactions balance per currency.
 func ValidateTransactions(txs []*parser.Transaction) error {
 	slog.Debug("Starting transaction validation", "transaction_count", len(txs))
@@ -24,7 +29,7 @@ func ValidateTransactions(txs []*parser.Transaction) error {
 			balances[currency] += amount
 		}
 		for currency, balance := range balances {
-			if balance != 0 {
+			if math.Abs(balance) >= balanceTolerance {
 				slog.Error("Transaction does not balance", "tx_index", i, "currency", currency, "balance", balance)
 				return fmt.Errorf("transaction does not balance for currency %s: %.2f", currency, balance)
 			}
@@ -25,6 +25,32 @@ func TestValidateTransactions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "balanced split with float error",
+			txs: []*parser.Transaction{
+				{
+					Postings: []parser.Posting{
+						{Amount: parser.Amount{Value: "-25.01", Currency: "USD"}},
+						{Amount: parser.Amount{Value: "12.51", Currency: "USD"}},
+						{Amount: parser.Amount{Value: "12.50", Currency: "USD"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "off by a cent",
+			txs: []*parser.Transaction{
+				{
+					Postings: []parser.Posting{
+						{Amount: parser.Amount{Value: "-25.01", Currency: "USD"}},
+						{Amount: parser.Amount{Value: "12.50", Currency: "USD"}},
+						{Amount: parser.Amount{Value: "12.50", Currency: "USD"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "unbalanced transactions",
 			txs: []*parser.Transaction{
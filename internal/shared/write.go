@@ -3,6 +3,7 @@ package shared
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -12,13 +13,41 @@ import (
 	"github.com/jason-riddle/ledger-go/internal/parser"
 )
 
-// WriteBeanFiles writes the main .bean file and placeholder balances/import files.
-func WriteBeanFiles(outputDir, pdfPath string, txs []*parser.Transaction) error {
-	baseName := strings.TrimSuffix(filepath.Base(pdfPath), ".pdf")
-	slog.Debug("Writing output files", "base_name", baseName, "output_dir", outputDir)
+// WriteOptions configures how generated files are laid out.
+type WriteOptions struct {
+	// Order controls entry ordering; the zero value sorts by date.
+	Order Order
+	// Format selects the journal syntax; the zero value is Beancount.
+	Format OutputFormat
+}
+
+// inputExtensions are stripped from source paths when naming output files.
+var inputExtensions = []string{".pdf", ".txt", ".text", ".json"}
+
+// OutputBaseName derives the output file stem from a source path by dropping
+// the directory and a known input extension (.pdf, .txt, .text or .json).
+func OutputBaseName(sourcePath string) string {
+	base := filepath.Base(sourcePath)
+	ext := filepath.Ext(base)
+	for _, known := range inputExtensions {
+		if strings.EqualFold(ext, known) {
+			return strings.TrimSuffix(base, ext)
+		}
+	}
+	return base
+}
 
-	// Write main .bean file
-	beanPath := filepath.Join(outputDir, baseName+".bean")
+// WriteBeanFiles writes the main journal file and placeholder balances/import files.
+// Files are named after sourcePath (see OutputBaseName) and their extensions
+// follow opts.Format (.bean, .ledger or .journal).
+func WriteBeanFiles(outputDir, sourcePath string, txs []*parser.Transaction, opts WriteOptions) error {
+	txs = SortTransactions(txs, opts.Order)
+	baseName := OutputBaseName(sourcePath)
+	ext := opts.Format.Extension()
+	slog.Debug("Writing output files", "base_name", baseName, "output_dir", outputDir, "order", opts.Order, "format", opts.Format)
+
+	// Write main journal file
+	beanPath := filepath.Join(outputDir, baseName+ext)
 	file, err := os.Create(beanPath)
 	if err != nil {
 		slog.Error("Failed to create bean file", "path", beanPath, "error", err)
@@ -26,51 +55,103 @@ func WriteBeanFiles(outputDir, pdfPath string, txs []*parser.Transaction) error
 	}
 	defer file.Close()
 
+	if err := WriteEntries(file, txs, opts.Format); err != nil {
+		slog.Error("Failed to write bean file", "path", beanPath, "error", err)
+		return err
+	}
+	slog.Info("Wrote main bean file", "path", beanPath, "transactions", len(txs))
+
+	// Placeholder for balances and import files (extend later)
+	balancesPath := filepath.Join(outputDir, baseName+".balances"+ext)
+	if err := os.WriteFile(balancesPath, []byte("; Balances placeholder\n"), 0644); err != nil {
+		slog.Error("Failed to write balances file", "path", balancesPath, "error", err)
+		return err
+	}
+	slog.Debug("Wrote balances placeholder file", "path", balancesPath)
+
+	importPath := filepath.Join(outputDir, baseName+".import"+ext)
+	if err := os.WriteFile(importPath, []byte("; Import placeholder\n"), 0644); err != nil {
+		slog.Error("Failed to write import file", "path", importPath, "error", err)
+		return err
+	}
+	slog.Debug("Wrote import placeholder file", "path", importPath)
+
+	return nil
+}
+
+// WriteEntries renders txs to w in the given format, in the order supplied.
+func WriteEntries(w io.Writer, txs []*parser.Transaction, format OutputFormat) error {
+	switch format {
+	case OutputBeancount:
+		return writeBeancount(w, txs)
+	case OutputLedger, OutputHledger:
+		return writeLedger(w, txs, format)
+	default:
+		return fmt.Errorf("unsupported output format %v", format)
+	}
+}
+
+func writeBeancount(w io.Writer, txs []*parser.Transaction) error {
+	var b strings.Builder
 	accountWidth, amountWidth := ComputePostingWidths(txs)
 	for _, tx := range txs {
 		if tx.Directive == "balance" {
-			fmt.Fprintln(file, FormatBalanceLine(tx, accountWidth, amountWidth))
-			fmt.Fprintln(file)
+			fmt.Fprintln(&b, FormatBalanceLine(tx, accountWidth, amountWidth))
+			fmt.Fprintln(&b)
 			continue
 		}
-		fmt.Fprintf(file, "%s * \"%s\"", tx.Date, tx.Payee)
+		if tx.Directive == "note" {
+			fmt.Fprintf(&b, "%s note %s \"%s\"\n", tx.Date, tx.NoteAccount, tx.Narration)
+			for _, key := range sortedLinkKeys(tx.Links) {
+				fmt.Fprintf(&b, "  %s: \"%s\"\n", key, tx.Links[key])
+			}
+			fmt.Fprintln(&b)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s \"%s\"", tx.Date, entryFlag(tx), tx.Payee)
 		if tx.Narration != "" {
-			fmt.Fprintf(file, " \"%s\"", tx.Narration)
+			fmt.Fprintf(&b, " \"%s\"", tx.Narration)
 		}
 		if len(tx.Tags) > 0 {
-			fmt.Fprintf(file, " %s", strings.Join(tx.Tags, " "))
-		}
-		fmt.Fprintln(file)
-		// Sort links for consistent output
-		var keys []string
-		for k := range tx.Links {
-			keys = append(keys, k)
+			fmt.Fprintf(&b, " %s", beancountTags(tx.Tags))
 		}
-		sort.Strings(keys)
-		for _, key := range keys {
-			fmt.Fprintf(file, "  %s: \"%s\"\n", key, tx.Links[key])
+		fmt.Fprintln(&b)
+		for _, key := range sortedLinkKeys(tx.Links) {
+			fmt.Fprintf(&b, "  %s: \"%s\"\n", key, tx.Links[key])
 		}
 		for _, p := range tx.Postings {
-			fmt.Fprintln(file, formatPostingLine(p, accountWidth, amountWidth))
+			fmt.Fprintln(&b, formatPostingLine(p, accountWidth, amountWidth))
 		}
-		fmt.Fprintln(file)
+		fmt.Fprintln(&b)
 	}
-	slog.Info("Wrote main bean file", "path", beanPath, "transactions", len(txs))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
 
-	// Placeholder for balances and import files (extend later)
-	balancesPath := filepath.Join(outputDir, baseName+".balances.bean")
-	if err := os.WriteFile(balancesPath, []byte("; Balances placeholder\n"), 0644); err != nil {
-		slog.Error("Failed to write balances file", "path", balancesPath, "error", err)
-		return err
+// beancountTags renders tags in Beancount syntax, adding the # a tag
+// stored without one needs.
+func beancountTags(tags []string) string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = "#" + strings.TrimPrefix(tag, "#")
 	}
-	slog.Debug("Wrote balances placeholder file", "path", balancesPath)
+	return strings.Join(names, " ")
+}
 
-	importPath := filepath.Join(outputDir, baseName+".import.bean")
-	if err := os.WriteFile(importPath, []byte("; Import placeholder\n"), 0644); err != nil {
-		slog.Error("Failed to write import file", "path", importPath, "error", err)
-		return err
+// entryFlag returns the transaction's flag, defaulting to cleared.
+func entryFlag(tx *parser.Transaction) string {
+	if tx.Flag == "" {
+		return parser.FlagCleared
 	}
-	slog.Debug("Wrote import placeholder file", "path", importPath)
+	return tx.Flag
+}
 
-	return nil
+// sortedLinkKeys returns metadata keys in a stable order for consistent output.
+func sortedLinkKeys(links map[string]string) []string {
+	var keys []string
+	for k := range links {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
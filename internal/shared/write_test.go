@@ -4,6 +4,7 @@ package shared
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jason-riddle/ledger-go/internal/parser"
@@ -24,7 +25,7 @@ func TestWriteBeanFiles(t *testing.T) {
 	}
 
 	pdfPath := filepath.Join(tempDir, "test.pdf")
-	err := WriteBeanFiles(tempDir, pdfPath, txs)
+	err := WriteBeanFiles(tempDir, pdfPath, txs, WriteOptions{})
 	if err != nil {
 		t.Fatalf("WriteBeanFiles failed: %v", err)
 	}
@@ -58,3 +59,95 @@ func TestWriteBeanFiles(t *testing.T) {
 		t.Errorf("Bean file content mismatch: got %q, want %q", string(content), expected)
 	}
 }
+
+func TestOutputBaseName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/tmp/statement.pdf", want: "statement"},
+		{path: "statement.PDF", want: "statement"},
+		{path: "tests/fixtures/cloverleaf/cloverleaf_2025-12-11_statement.txt", want: "cloverleaf_2025-12-11_statement"},
+		{path: "edited.json", want: "edited"},
+		{path: "Statement 11.01.2025", want: "Statement 11.01.2025"},
+		{path: "stdin", want: "stdin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := OutputBaseName(tt.path); got != tt.want {
+				t.Errorf("OutputBaseName(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteEntriesFlag(t *testing.T) {
+	txs := []*parser.Transaction{
+		{
+			Date:     "2025-11-05",
+			Flag:     parser.FlagReview,
+			Payee:    "Tenant",
+			Links:    map[string]string{parser.ReviewKey: "OCR page 2"},
+			Postings: []parser.Posting{{Account: "Assets:Cash", Amount: parser.Amount{Value: "10.00", Currency: "USD"}}, {Account: "Income:Rent", Amount: parser.Amount{Value: "-10.00", Currency: "USD"}}},
+		},
+		{
+			Date:     "2025-11-06",
+			Payee:    "Owner",
+			Tags:     []string{"imported", "#mortgage"},
+			Postings: []parser.Posting{{Account: "Assets:Cash", Amount: parser.Amount{Value: "-5.00", Currency: "USD"}}, {Account: "Equity:Draw", Amount: parser.Amount{Value: "5.00", Currency: "USD"}}},
+		},
+	}
+
+	tests := []struct {
+		format OutputFormat
+		want   []string
+	}{
+		{format: OutputBeancount, want: []string{`2025-11-05 ! "Tenant"`, `review: "OCR page 2"`, `2025-11-06 * "Owner" #imported #mortgage`}},
+		{format: OutputLedger, want: []string{"2025/11/05 ! ", "; review: OCR page 2", "2025/11/06 * "}},
+		{format: OutputHledger, want: []string{"2025-11-05 ! Tenant", "2025-11-06 * Owner"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteEntries(&b, txs, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteEntriesNote(t *testing.T) {
+	txs := []*parser.Transaction{{
+		Date:        "2025-10-13",
+		Directive:   "note",
+		NoteAccount: "Expenses:Repairs:206-Hoover-Ave",
+		Narration:   "Open work order 110552: Trash out",
+		Links:       map[string]string{"work-order": "110552", "estimate": "0.00 USD"},
+	}}
+
+	tests := []struct {
+		format OutputFormat
+		want   string
+	}{
+		{format: OutputBeancount, want: "2025-10-13 note Expenses:Repairs:206-Hoover-Ave \"Open work order 110552: Trash out\"\n  estimate: \"0.00 USD\"\n  work-order: \"110552\"\n"},
+		{format: OutputLedger, want: "; 2025/10/13 note Expenses:Repairs:206-Hoover-Ave: Open work order 110552: Trash out\n;   estimate: 0.00 USD\n;   work-order: 110552\n"},
+		{format: OutputHledger, want: "; 2025-10-13 note Expenses:Repairs:206-Hoover-Ave: Open work order 110552: Trash out\n;   estimate: 0.00 USD\n;   work-order: 110552\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteEntries(&b, txs, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(b.String()); got != strings.TrimSpace(tt.want) {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
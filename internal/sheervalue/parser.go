@@ -2,10 +2,15 @@
 package sheervalue
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/jason-riddle/ledger-go/internal/layout"
+	"github.com/jason-riddle/ledger-go/internal/normalize"
 	"github.com/jason-riddle/ledger-go/internal/parser"
 )
 
@@ -18,227 +23,404 @@ func NewParser() parser.Parser {
 
 // Parse extracts transactions from SheerValue statement text.
 func (p *sheerValueParser) Parse(text string) ([]*parser.Transaction, error) {
+	return p.ParseContext(context.Background(), text)
+}
+
+// ParseContext extracts transactions from SheerValue statement text, checking ctx
+// before each line.
+func (p *sheerValueParser) ParseContext(ctx context.Context, text string) ([]*parser.Transaction, error) {
+	txs, _, err := p.ParseCoverage(ctx, text)
+	return txs, err
+}
+
+// institution names the parser in errors.
+const institution = "sheervalue"
+
+// detailsSection is the section transactions are read from. Its heading is
+// letter-spaced in extracted text ("Detail tr an saction s").
+const detailsSection = "Detail transactions"
+
+var (
+	subtotalRe     = regexp.MustCompile(`^\s*Total\b`)
+	additionsRe    = regexp.MustCompile(`(?i)^\s*Additions\s+to\s+cash\s*$`)
+	subtractionsRe = regexp.MustCompile(`(?i)^\s*Subtractions\s+from\s+cash\s*$`)
+	byPayerRe      = regexp.MustCompile(`(?:^|\s)(by\s+.+)$`)
+)
+
+// errNoCashDirection reports an Amount column entry printed before the
+// statement said whether entries add to or subtract from cash.
+var errNoCashDirection = errors.New("amount outside additions to and subtractions from cash")
+
+// ParseCoverage is ParseContext that also classifies every line of the
+// detail transactions section, from its heading to the ending cash balance,
+// as consumed, ignored or unrecognized.
+//
+// Entries are read by column from the table header on each page, so any GL
+// account the statement prints is imported and routed through mapAccount.
+// The summary's security deposits and property reserve are asserted as
+// balances on the date of the ending cash balance.
+func (p *sheerValueParser) ParseCoverage(ctx context.Context, text string) ([]*parser.Transaction, *parser.Coverage, error) {
 	var txs []*parser.Transaction
+	var errs parser.ParseErrors
+	coverage := &parser.Coverage{}
+	inDetails := false
+	// columns is the detail table layout from the latest column header.
+	var columns []layout.Column
+	// inflow is set by the "Additions to cash" and "Subtractions from cash"
+	// subheadings; nil until the first one is seen.
+	var inflow *bool
+	// wrapped holds the indexes of lines already merged into an entry above.
+	wrapped := map[int]bool{}
 
 	lines := strings.Split(text, "\n")
+	rows := layout.TextLines(text)
+	// endDate is the date of the ending cash balance, which the summary's
+	// deposit and reserve balances are asserted on.
+	var endDate string
 	beginBalanceRe := regexp.MustCompile(`Beginning cash balance as of\s+(\d{1,2}\s*/\s*\d{1,2}\s*/\s*\d{4}).*?\$?\s*([\(]?\d[\d,\s]*\.\d{2}[)]?)`)
 	endBalanceRe := regexp.MustCompile(`Ending cash balance as of\s+(\d{1,2}\s*/\s*\d{1,2}\s*/\s*\d{4}).*?\$?\s*([\(]?\d[\d,\s]*\.\d{2}[)]?)`)
-	lineRe := regexp.MustCompile(`^\s*(\d{1,2}/\d{1,2}/\d{4})\s+(2943\s+Butterfly\s+Palm|206\s+Hoover\s+Avenue)\s+(\S+)\s+(Rent\s+Income|Pet\s+Rent|Late\s+Fee|Management|Owner\s+Draw|Repairs)\s+(.+?)\s+([\(]?\d[\d,]*\.\d{2}[)]?)\s+([\(]?\d[\d,]*\.\d{2}[)]?)\s*$`)
 
 	for i := 0; i < len(lines); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		line := lines[i]
+		section := ""
+		if inDetails {
+			section = detailsSection
+		}
+		note := func(status parser.LineStatus, rule string) {
+			coverage.Add(i+1, line, section, status, rule)
+		}
+		// fail records a field of this line that could not be converted.
+		fail := func(field string, err error) {
+			errs = append(errs, parser.NewParseError(institution, i+1, line, field, err))
+			note(parser.LineUnrecognized, "parse error")
+		}
+		if isDetailsHeading(line) {
+			inDetails = true
+			section = detailsSection
+			note(parser.LineIgnored, "section heading")
+			continue
+		}
 		if beginMatch := beginBalanceRe.FindStringSubmatch(line); beginMatch != nil {
-			dateStr := formatDateSlash(beginMatch[1])
-			amountStr, _ := normalizeAmount(beginMatch[2])
+			dateStr, err := normalize.Date(beginMatch[1])
+			if err != nil {
+				fail(beginMatch[1], err)
+				continue
+			}
+			amountStr, err := normalize.Amount(beginMatch[2])
+			if err != nil {
+				fail(beginMatch[2], err)
+				continue
+			}
 			txs = append(txs, &parser.Transaction{
 				Date:           dateStr,
 				Directive:      "balance",
 				BalanceAccount: "Assets:Property-Management:SheerValue-PM",
 				BalanceAmount:  parser.Amount{Value: amountStr, Currency: "USD"},
+				Line:           i + 1,
 			})
+			note(parser.LineConsumed, "balance")
 			continue
 		}
 		if endMatch := endBalanceRe.FindStringSubmatch(line); endMatch != nil {
-			dateStr := formatDateSlash(endMatch[1])
-			amountStr, _ := normalizeAmount(endMatch[2])
+			dateStr, err := normalize.Date(endMatch[1])
+			if err != nil {
+				fail(endMatch[1], err)
+				continue
+			}
+			amountStr, err := normalize.Amount(endMatch[2])
+			if err != nil {
+				fail(endMatch[2], err)
+				continue
+			}
 			txs = append(txs, &parser.Transaction{
 				Date:           dateStr,
 				Directive:      "balance",
 				BalanceAccount: "Assets:Property-Management:SheerValue-PM",
 				BalanceAmount:  parser.Amount{Value: amountStr, Currency: "USD"},
+				Line:           i + 1,
 			})
+			endDate = dateStr
+			note(parser.LineConsumed, "balance")
+			inDetails = false
 			continue
 		}
-
-		match := lineRe.FindStringSubmatch(line)
-		if match == nil {
+		if !inDetails {
+			note(parser.LineOutside, "")
+			continue
+		}
+		if wrapped[i] {
+			note(parser.LineConsumed, "wrapped text")
+			continue
+		}
+		if header := detailColumns(rows[i]); header != nil {
+			columns = header
+			note(parser.LineIgnored, "column header")
+			continue
+		}
+		if additionsRe.MatchString(line) || subtractionsRe.MatchString(line) {
+			in := additionsRe.MatchString(line)
+			inflow = &in
+			note(parser.LineConsumed, "cash direction")
 			continue
 		}
 
-		dateStr := match[1]
-		property := cleanSpaces(match[2])
-		accountType := cleanSpaces(match[4])
-		nameMemo := strings.TrimSpace(match[5])
-		amountAbs, isNegative := normalizeAmount(match[6])
-		sign := 1
-		if isNegative {
-			sign = -1
-		}
-		if accountType == "Management" {
-			accountType = "Management Fees"
-		}
-		propertySlug := propertySlug(property)
-		account, accountKind := p.mapAccount(accountType, propertySlug)
-
-		payee := payeeFromMemo(nameMemo, accountKind)
-		if accountType == "Management Fees" && i+1 < len(lines) {
-			nextLine := lines[i+1]
-			if strings.Contains(nextLine, "Fees") {
-				payeeSuffix := extractManagementSuffix(nextLine)
-				if payeeSuffix != "" {
-					payee = cleanSpaces(payee + " " + payeeSuffix)
-				}
+		cells := rows[i].CellsByName(columns)
+		if !isEntryRow(cells) {
+			note(classifyDetailLine(line))
+			continue
+		}
+		for next := i + 1; next < len(lines) && isWrappedRow(lines[next], rows[next].CellsByName(columns)); next++ {
+			for name, text := range rows[next].CellsByName(columns) {
+				cells[name] = cleanSpaces(cells[name] + " " + text)
 			}
+			wrapped[next] = true
 		}
-		reversed := isNegative || strings.Contains(strings.ToUpper(nameMemo), "REVERSED")
-		if reversed {
-			if suffix := findBySuffix(lines, i+1); suffix != "" && !strings.Contains(payee, suffix) {
-				payee = cleanSpaces(payee + " " + suffix)
+
+		dateStr, err := normalize.Date(cells["Date"])
+		if err != nil {
+			fail(cells["Date"], err)
+			continue
+		}
+		// amount is the change in the management account's cash.
+		var amount string
+		switch {
+		case cells["Cash In"] != "":
+			amount, err = normalize.Amount(cells["Cash In"])
+			if err != nil {
+				fail(cells["Cash In"], err)
+				continue
+			}
+		case cells["Cash Out"] != "":
+			amount, err = normalize.Amount(cells["Cash Out"])
+			if err != nil {
+				fail(cells["Cash Out"], err)
+				continue
+			}
+			amount = normalize.Negate(amount)
+		default:
+			amount, err = normalize.Amount(cells["Amount"])
+			if err != nil {
+				fail(cells["Amount"], err)
+				continue
+			}
+			if inflow == nil {
+				fail(cells["Amount"], errNoCashDirection)
+				continue
+			}
+			if !*inflow {
+				amount = normalize.Negate(amount)
 			}
 		}
+		note(parser.LineConsumed, "transaction")
 
-		narration := fmt.Sprintf("Memo: %s - %s", property, accountType)
+		property := cells["Property"]
+		glAccount := cells["Account"]
+		memo := cells["Memo"]
+		addsCash := cells["Cash In"] != "" || (cells["Cash Out"] == "" && inflow != nil && *inflow)
+		account, reason := p.mapAccount(glAccount, memo, normalize.PropertySlug(property))
+
+		payee := cells["Name"] + cells["Payee"]
+		// Receipts name the payer in the memo ("by Layla Noble-Davis").
+		if m := byPayerRe.FindStringSubmatch(memo); addsCash && m != nil {
+			payee = cleanSpaces(payee + " " + m[1])
+		}
+
+		narration := fmt.Sprintf("Memo: %s - %s", property, glAccount)
 		tags := []string{"#imported"}
+		reversed := strings.HasPrefix(amount, "-") == addsCash || strings.Contains(strings.ToUpper(memo), "REVERSED")
 		if reversed {
 			narration += " - REVERSED"
 			tags = append(tags, "#reversed")
 		}
 
-		var postings []parser.Posting
-		if accountKind == accountKindIncome {
-			postings = []parser.Posting{
-				{Account: "Assets:Property-Management:SheerValue-PM", Amount: parser.Amount{Value: signedAmount(amountAbs, sign), Currency: "USD"}},
-				{Account: account, Amount: parser.Amount{Value: signedAmount(amountAbs, -sign), Currency: "USD"}},
-			}
-		} else {
-			postings = []parser.Posting{
-				{Account: account, Amount: parser.Amount{Value: signedAmount(amountAbs, sign), Currency: "USD"}},
-				{Account: "Assets:Property-Management:SheerValue-PM", Amount: parser.Amount{Value: signedAmount(amountAbs, -sign), Currency: "USD"}},
-			}
+		postings := []parser.Posting{
+			{Account: "Assets:Property-Management:SheerValue-PM", Amount: parser.Amount{Value: amount, Currency: "USD"}},
+			{Account: account, Amount: parser.Amount{Value: normalize.Negate(amount), Currency: "USD"}},
 		}
 		parser.OrderPostingsBySign(postings)
 
 		tx := &parser.Transaction{
-			Date:      formatDateSlash(dateStr),
+			Date:      dateStr,
 			Payee:     payee,
 			Narration: narration,
 			Tags:      tags,
 			Links:     p.mapLinks(),
 			Postings:  postings,
+			Line:      i + 1,
+		}
+		if reason != "" {
+			tx.MarkForReview(reason)
 		}
 		txs = append(txs, tx)
 	}
 
-	return txs, nil
-}
-
-// mapLinks returns the standard links
-func (p *sheerValueParser) mapLinks() map[string]string {
-	return map[string]string{
-		"comments": "",
+	if endDate != "" {
+		totals, err := p.ParseSummary(ctx, text)
+		var summaryErrs parser.ParseErrors
+		switch {
+		case errors.As(err, &summaryErrs):
+			errs = append(errs, summaryErrs...)
+		case err != nil:
+			return nil, nil, err
+		}
+		txs = parser.DropUnpostedHoldings(append(txs, summaryBalances(totals, endDate)...))
 	}
+	return txs, coverage, errs.Err()
 }
 
-type accountKind int
+// detailHeadings are the detail table headings the parser reads, left to
+// right. Statements print either one Amount column, signed by the cash
+// subheading above it, or separate Cash In and Cash Out columns; some print
+// Payee rather than Name.
+var detailHeadings = []string{"Date", "Property", "Unit", "Account", "Name", "Payee", "Memo", "Cash In", "Cash Out", "Amount", "Balance"}
 
-const (
-	accountKindIncome accountKind = iota
-	accountKindExpense
-)
+// textHeadings are the columns whose text is left-aligned under the heading
+// and may run past the midpoint to the next one.
+var textHeadings = []string{"Property", "Unit", "Account", "Name", "Payee", "Memo"}
 
-func (p *sheerValueParser) mapAccount(accountType, propertySlug string) (string, accountKind) {
-	switch accountType {
-	case "Rent Income":
-		return fmt.Sprintf("Income:Rent:%s", propertySlug), accountKindIncome
-	case "Pet Rent":
-		return fmt.Sprintf("Income:Pet-Fee:%s", propertySlug), accountKindIncome
-	case "Late Fee":
-		return fmt.Sprintf("Income:Late-Rent-Fee:%s", propertySlug), accountKindIncome
-	case "Management Fees":
-		return fmt.Sprintf("Expenses:Management-Fees:%s", propertySlug), accountKindExpense
-	case "Owner Draw":
-		return "Equity:Owner-Distributions:Owner-Draw", accountKindExpense
-	case "Repairs":
-		return fmt.Sprintf("Expenses:Repairs:%s", propertySlug), accountKindExpense
-	default:
-		return "Expenses:Other", accountKindExpense
+// amountWidth is the widest amount the right-aligned amount columns hold,
+// like (10,000.00) with a space either side.
+const amountWidth = 13 * layout.TextCellWidth
+
+// detailColumns returns the columns of a detail table header line, or nil
+// when line is not one. A header needs Date, Property and Account headings
+// and at least one amount heading.
+func detailColumns(line layout.Line) []layout.Column {
+	columns := layout.FindColumns(line, detailHeadings...)
+	has := func(name string) bool {
+		return slices.ContainsFunc(columns, func(c layout.Column) bool { return c.Name == name })
 	}
+	if !has("Date") || !has("Property") || !has("Account") || !(has("Amount") || has("Cash In") || has("Cash Out")) {
+		return nil
+	}
+	layout.AlignLeft(columns, textHeadings...)
+	layout.AlignRight(columns, amountWidth, "Cash In", "Cash Out", "Amount", "Balance")
+	return columns
 }
 
-var multiSpaceRe = regexp.MustCompile(`\s{2,}`)
-
-func payeeFromMemo(nameMemo string, accountKind accountKind) string {
-	if accountKind == accountKindIncome {
-		cleaned := cleanSpaces(nameMemo)
-		if idx := strings.Index(strings.ToUpper(cleaned), "REVERSED"); idx != -1 {
-			cleaned = cleanSpaces(cleaned[:idx])
-		}
-		return cleaned
-	}
-	parts := multiSpaceRe.Split(strings.TrimSpace(nameMemo), -1)
-	if len(parts) == 0 {
-		return cleanSpaces(nameMemo)
+// isEntryRow reports whether cells hold a dated entry with an amount.
+func isEntryRow(cells map[string]string) bool {
+	date := cells["Date"]
+	if date == "" || date[0] < '0' || date[0] > '9' {
+		return false
 	}
-	return cleanSpaces(parts[0])
+	return cells["Amount"] != "" || cells["Cash In"] != "" || cells["Cash Out"] != ""
 }
 
-func extractManagementSuffix(line string) string {
-	idx := strings.Index(line, "Fees")
-	if idx == -1 {
-		return ""
+// isWrappedRow reports whether line continues the cells of the entry above
+// it: undated text with no amounts that is not a subheading or subtotal.
+func isWrappedRow(line string, cells map[string]string) bool {
+	switch {
+	case strings.TrimSpace(line) == "", strings.Contains(line, "\f"), parser.HasAmount(line):
+		return false
+	case cells["Date"] != "", subtotalRe.MatchString(line), additionsRe.MatchString(line), subtractionsRe.MatchString(line):
+		return false
+	default:
+		return true
 	}
-	suffix := strings.TrimSpace(line[idx+len("Fees"):])
-	return cleanSpaces(suffix)
 }
 
-func findBySuffix(lines []string, start int) string {
-	for i := start; i < len(lines) && i < start+3; i++ {
-		lower := strings.ToLower(lines[i])
-		if idx := strings.Index(lower, "by "); idx != -1 {
-			return cleanSpaces(lines[i][idx:])
-		}
+// isDetailsHeading reports whether line is the detail transactions heading,
+// ignoring the spacing extraction inserts between letters.
+func isDetailsHeading(line string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(line), ""), "Detailtransactions")
+}
+
+// classifyDetailLine classifies a detail transactions line that is not a
+// transaction row or balance.
+func classifyDetailLine(line string) (parser.LineStatus, string) {
+	switch {
+	case strings.TrimSpace(line) == "":
+		return parser.LineIgnored, "blank"
+	case subtotalRe.MatchString(line):
+		return parser.LineIgnored, "subtotal"
+	case !parser.HasAmount(line):
+		// Page headers and footers.
+		return parser.LineIgnored, "text without amounts"
+	default:
+		return parser.LineUnrecognized, ""
 	}
-	return ""
 }
 
-func normalizeAmount(amount string) (string, bool) {
-	trimmed := strings.TrimSpace(amount)
-	trimmed = strings.TrimPrefix(trimmed, "$")
-	isNegative := strings.HasPrefix(trimmed, "(") && strings.HasSuffix(trimmed, ")")
-	trimmed = strings.TrimPrefix(trimmed, "(")
-	trimmed = strings.TrimSuffix(trimmed, ")")
-	if strings.HasPrefix(trimmed, "-") {
-		isNegative = true
-		trimmed = strings.TrimPrefix(trimmed, "-")
+// mapLinks returns the standard links
+func (p *sheerValueParser) mapLinks() map[string]string {
+	return map[string]string{
+		"comments": "",
 	}
-	trimmed = strings.ReplaceAll(trimmed, ",", "")
-	trimmed = strings.ReplaceAll(trimmed, " ", "")
-	return trimmed, isNegative
 }
 
-func signedAmount(amount string, sign int) string {
-	if sign < 0 {
-		return fmt.Sprintf("-%s", amount)
+// mapAccount routes a GL account to a ledger account. Utilities booked to
+// a single GL account are split by the memo, e.g. "Electric service". GL
+// accounts without a mapping, and rows missing the GL account or a property
+// the account is booked per, go to the suspense account with the reason
+// they need review.
+func (p *sheerValueParser) mapAccount(glAccount, memo, propertySlug string) (account, reason string) {
+	if glAccount == "Owner Draw" {
+		return "Equity:Owner-Distributions:Owner-Draw", ""
+	}
+	if propertySlug == "" {
+		return parser.SuspenseAccount, parser.UnmatchedReason
+	}
+	switch glAccount {
+	case "Rent Income":
+		return fmt.Sprintf("Income:Rent:%s", propertySlug), ""
+	case "Pet Rent":
+		return fmt.Sprintf("Income:Pet-Fee:%s", propertySlug), ""
+	case "Late Fee", "Late Fee Income":
+		return fmt.Sprintf("Income:Late-Rent-Fee:%s", propertySlug), ""
+	case "Management", "Management Fees":
+		return fmt.Sprintf("Expenses:Management-Fees:%s", propertySlug), ""
+	case "Repairs":
+		return fmt.Sprintf("Expenses:Repairs:%s", propertySlug), ""
+	case "Cleaning", "Cleaning and Maintenance", "Landscaping", "Pest Control":
+		return fmt.Sprintf("Expenses:Cleaning---Maintenance:%s", propertySlug), ""
+	case "Advertising":
+		return fmt.Sprintf("Expenses:Advertising:%s", propertySlug), ""
+	case "Insurance":
+		return fmt.Sprintf("Expenses:Insurance:%s", propertySlug), ""
+	case "Property Tax", "Property Taxes":
+		return fmt.Sprintf("Expenses:Property-Taxes:%s", propertySlug), ""
+	case "HOA", "HOA Dues", "HOA Fees":
+		return fmt.Sprintf("Expenses:Other:HOA:%s", propertySlug), ""
+	case "Electric", "Electricity":
+		return fmt.Sprintf("Expenses:Utilities:Electric:%s", propertySlug), ""
+	case "Water", "Water/Sewer", "Water & Sewer":
+		return fmt.Sprintf("Expenses:Utilities:Water:%s", propertySlug), ""
+	case "Gas":
+		return fmt.Sprintf("Expenses:Utilities:Gas:%s", propertySlug), ""
+	case "Utilities":
+		if utility := utilityAccount(memo); utility != "" {
+			return fmt.Sprintf("Expenses:Utilities:%s:%s", utility, propertySlug), ""
+		}
+		return parser.SuspenseAccount, parser.UnmatchedReason
+	case "Security Deposit", "Security Deposits", "Security Deposit Refund":
+		return parser.DepositAccount(propertySlug), ""
+	case "Property Reserve", "Reserve", "Reserve Contribution":
+		return parser.ReserveAccount(propertySlug), ""
+	default:
+		return parser.SuspenseAccount, parser.UnmatchedReason
 	}
-	return amount
 }
 
-func formatDateSlash(dateStr string) string {
-	clean := strings.ReplaceAll(dateStr, " ", "")
-	parts := strings.Split(clean, "/")
-	if len(parts) != 3 {
-		return strings.TrimSpace(dateStr)
+// utilityAccount names the utility a memo describes, or "" when it names
+// none.
+func utilityAccount(memo string) string {
+	memo = strings.ToLower(memo)
+	switch {
+	case strings.Contains(memo, "electric"):
+		return "Electric"
+	case strings.Contains(memo, "water"), strings.Contains(memo, "sewer"):
+		return "Water"
+	case strings.Contains(memo, "gas"):
+		return "Gas"
+	default:
+		return ""
 	}
-	return fmt.Sprintf("%s-%02s-%02s", parts[2], parts[0], parts[1])
 }
 
 func cleanSpaces(value string) string {
 	return strings.Join(strings.Fields(value), " ")
 }
-
-func propertySlug(property string) string {
-	parts := strings.Fields(property)
-	for i, part := range parts {
-		switch part {
-		case "Avenue":
-			parts[i] = "Ave"
-		case "Street":
-			parts[i] = "St"
-		case "Road":
-			parts[i] = "Rd"
-		case "Drive":
-			parts[i] = "Dr"
-		}
-	}
-	return strings.Join(parts, "-")
-}
@@ -2,13 +2,16 @@
 package sheervalue_test
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
-	"sort"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/jason-riddle/ledger-go/internal/parser"
 	"github.com/jason-riddle/ledger-go/internal/shared"
 	"github.com/jason-riddle/ledger-go/internal/sheervalue"
 )
@@ -27,47 +30,264 @@ func TestGoldenFiles(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	txs = shared.SortTransactions(txs, shared.OrderDate)
 
-	// Format to .bean
-	var lines []string
-	accountWidth, amountWidth := shared.ComputePostingWidths(txs)
-	for _, tx := range txs {
-		if tx.Directive == "balance" {
-			lines = append(lines, shared.FormatBalanceLine(tx, accountWidth, amountWidth))
-			lines = append(lines, "")
+	for _, format := range []shared.OutputFormat{shared.OutputBeancount, shared.OutputLedger, shared.OutputHledger} {
+		t.Run(format.String(), func(t *testing.T) {
+			// Format in date order
+			var output strings.Builder
+			if err := shared.WriteEntries(&output, txs, format); err != nil {
+				t.Fatal(err)
+			}
+
+			// Load golden
+			goldenPath := filepath.Join("..", "..", "tests", "golden", "sheervalue", "sheervalue_2025_multi_property_statement"+format.Extension())
+			golden, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Compare
+			if strings.TrimSpace(output.String()) != strings.TrimSpace(string(golden)) {
+				t.Errorf("Output does not match golden file.\nGot:\n%s\n\nWant:\n%s", output.String(), string(golden))
+			}
+		})
+	}
+}
+
+func TestParseCoverage(t *testing.T) {
+	fixturePath := filepath.Join("..", "..", "tests", "fixtures", "sheervalue", "multi_prop", "sheervalue_2025_multi_property_statement.txt")
+	fixture, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An undated row no rule reads.
+	lines := strings.Split(string(fixture), "\n")
+	lines = slices.Insert(lines, 129, "   Adjustment pending review                                                                                 45.00")
+
+	tests := []struct {
+		name      string
+		text      string
+		wantLines []int
+	}{
+		{name: "fixture", text: string(fixture)},
+		{name: "undated row", text: strings.Join(lines, "\n"), wantLines: []int{130}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, coverage, err := sheervalue.NewParser().ParseCoverage(context.Background(), tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, line := range coverage.Unrecognized() {
+				got = append(got, line.Line)
+			}
+			if !reflect.DeepEqual(got, tt.wantLines) {
+				t.Errorf("unrecognized lines = %v, want %v", got, tt.wantLines)
+			}
+
+			rules := map[int]string{}
+			for _, line := range coverage.Lines {
+				rules[line.Line] = line.Status.String() + " " + line.Rule
+			}
+			want := map[int]string{
+				40: "outside ",
+				69: "ignored section heading",
+				70: "ignored column header",
+				71: "consumed balance",
+				73: "consumed transaction",
+				72: "consumed cash direction",
+				85: "consumed wrapped text",
+			}
+			for line, rule := range want {
+				if rules[line] != rule {
+					t.Errorf("line %d = %q, want %q", line, rules[line], rule)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGLAccounts(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "sheervalue", "multi_prop", "sheervalue_2025_multi_property_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Utilities, HOA dues, a reserve contribution, a repair with no
+	// property, advertising, a utility the memo does not name and a GL
+	// account the parser has no mapping for, plus a deposit, aligned to the
+	// page's column header. The deposit's account wraps onto a second line.
+	lines := strings.Split(string(fixture), "\n")
+	subtractions := []string{
+		"        8/6/2025 206 Hoover Avenue             Property   Utilities    CPS Energy             Electric service                          89.40            4,133.10",
+		"        8/6/2025 206 Hoover Avenue             Property   HOA Dues     Hoover HOA             Quarterly dues                           210.00            3,923.10",
+		"        8/6/2025 2943 Butterfly Palm           Property   Reserve      Sheer Value            Reserve contribution                     250.00            3,673.10",
+		"        8/6/2025                               Property   Repairs      Ace Handyman           Gutter repair                             75.00            3,598.10",
+		"        8/6/2025 206 Hoover Avenue             Property   Advertising  Zillow                 Rental listing                            45.00            3,553.10",
+		"        8/6/2025 206 Hoover Avenue             Property   Utilities    SAWS                   Service fee                               12.00            3,541.10",
+		"        8/6/2025 206 Hoover Avenue             Property   Pool Service Blue Pools             Monthly cleaning                          80.00            3,461.10",
+	}
+	additions := []string{
+		"        1/9/2025 206 Hoover Avenue             1      Security      Unit 1 - George Mahara    Deposit by George Mahara                 1,150.00            5,850.00",
+		"                                                      Deposit",
+	}
+	lines = slices.Insert(lines, 225, subtractions...)
+	lines = slices.Insert(lines, 73, additions...)
+
+	txs, coverage, err := sheervalue.NewParser().ParseCoverage(context.Background(), strings.Join(lines, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := coverage.Unrecognized(); len(got) != 0 {
+		t.Errorf("unrecognized lines = %v, want none", got)
+	}
+
+	tests := []struct {
+		line      int
+		payee     string
+		narration string
+		postings  []string
+		// review is the reason the entry is flagged for review, or "".
+		review string
+	}{
+		{
+			line:      74,
+			payee:     "Unit 1 - George Mahara by George Mahara",
+			narration: "Memo: 206 Hoover Avenue - Security Deposit",
+			postings:  []string{"Liabilities:Security-Deposits-Owed:206-Hoover-Ave -1150.00", "Assets:Property-Management:SheerValue-PM 1150.00"},
+		},
+		{
+			line:      228,
+			payee:     "CPS Energy",
+			narration: "Memo: 206 Hoover Avenue - Utilities",
+			postings:  []string{"Assets:Property-Management:SheerValue-PM -89.40", "Expenses:Utilities:Electric:206-Hoover-Ave 89.40"},
+		},
+		{
+			line:      229,
+			payee:     "Hoover HOA",
+			narration: "Memo: 206 Hoover Avenue - HOA Dues",
+			postings:  []string{"Assets:Property-Management:SheerValue-PM -210.00", "Expenses:Other:HOA:206-Hoover-Ave 210.00"},
+		},
+		{
+			line:      230,
+			payee:     "Sheer Value",
+			narration: "Memo: 2943 Butterfly Palm - Reserve",
+			postings:  []string{"Assets:Property-Management:SheerValue-PM -250.00", "Assets:Property-Reserves:2943-Butterfly-Palm 250.00"},
+		},
+		{
+			line:      231,
+			payee:     "Ace Handyman",
+			narration: "Memo:  - Repairs",
+			postings:  []string{"Assets:Property-Management:SheerValue-PM -75.00", "Expenses:Unidentified 75.00"},
+			review:    parser.UnmatchedReason,
+		},
+		{
+			line:      232,
+			payee:     "Zillow",
+			narration: "Memo: 206 Hoover Avenue - Advertising",
+			postings:  []string{"Assets:Property-Management:SheerValue-PM -45.00", "Expenses:Advertising:206-Hoover-Ave 45.00"},
+		},
+		{
+			line:      233,
+			payee:     "SAWS",
+			narration: "Memo: 206 Hoover Avenue - Utilities",
+			postings:  []string{"Assets:Property-Management:SheerValue-PM -12.00", "Expenses:Unidentified 12.00"},
+			review:    parser.UnmatchedReason,
+		},
+		{
+			line:      234,
+			payee:     "Blue Pools",
+			narration: "Memo: 206 Hoover Avenue - Pool Service",
+			postings:  []string{"Assets:Property-Management:SheerValue-PM -80.00", "Expenses:Unidentified 80.00"},
+			review:    parser.UnmatchedReason,
+		},
+	}
+	for _, tt := range tests {
+		i := slices.IndexFunc(txs, func(tx *parser.Transaction) bool { return tx.Line == tt.line })
+		if i == -1 {
+			t.Errorf("no entry from line %d", tt.line)
 			continue
 		}
-		line := fmt.Sprintf("%s * \"%s\"", tx.Date, tx.Payee)
-		if tx.Narration != "" {
-			line += fmt.Sprintf(" \"%s\"", tx.Narration)
+		tx := txs[i]
+		var postings []string
+		for _, p := range tx.Postings {
+			postings = append(postings, p.Account+" "+p.Amount.Value)
+		}
+		if tx.Payee != tt.payee || tx.Narration != tt.narration || !reflect.DeepEqual(postings, tt.postings) {
+			t.Errorf("line %d = %q %q %v, want %q %q %v", tt.line, tx.Payee, tx.Narration, postings, tt.payee, tt.narration, tt.postings)
 		}
-		line += " " + strings.Join(tx.Tags, " ")
-		lines = append(lines, line)
-		// Sort link keys for consistent output
-		var keys []string
-		for key := range tx.Links {
-			keys = append(keys, key)
+		if review := tx.Links[parser.ReviewKey]; review != tt.review {
+			t.Errorf("line %d review = %q, want %q", tt.line, review, tt.review)
 		}
-		sort.Strings(keys)
-		for _, key := range keys {
-			lines = append(lines, fmt.Sprintf("  %s: \"%s\"", key, tx.Links[key]))
+	}
+}
+
+func TestParseCashColumns(t *testing.T) {
+	text := "" +
+		"Detail transactions\n" +
+		"   Date       Property            Account        Payee            Memo                     Cash In     Cash Out\n" +
+		"Beginning cash balance as of 1/1/2025                                                                  $500.00\n" +
+		"   1/2/2025   12 Oak Street       Rent Income    Ann Lee          by Ann Lee              1,000.00\n" +
+		"   1/5/2025   12 Oak Street       Repairs        Ace Handyman     Gutter repair                            45.00\n" +
+		"Ending cash balance as of 1/31/2025                                                                  $1,455.00\n"
+
+	txs, coverage, err := sheervalue.NewParser().ParseCoverage(context.Background(), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := coverage.Unrecognized(); len(got) != 0 {
+		t.Errorf("unrecognized lines = %v, want none", got)
+	}
+
+	var got []string
+	for _, tx := range txs {
+		if tx.Directive == "balance" {
+			continue
 		}
 		for _, p := range tx.Postings {
-			lines = append(lines, shared.FormatPostingLine(p, accountWidth, amountWidth))
+			got = append(got, tx.Payee+": "+p.Account+" "+p.Amount.Value)
 		}
-		lines = append(lines, "")
 	}
-	output := strings.Join(lines, "\n")
+	want := []string{
+		"Ann Lee by Ann Lee: Income:Rent:12-Oak-St -1000.00",
+		"Ann Lee by Ann Lee: Assets:Property-Management:SheerValue-PM 1000.00",
+		"Ace Handyman: Assets:Property-Management:SheerValue-PM -45.00",
+		"Ace Handyman: Expenses:Repairs:12-Oak-St 45.00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("postings = %q, want %q", got, want)
+	}
+}
 
-	// Load golden
-	goldenPath := filepath.Join("..", "..", "tests", "golden", "sheervalue", "sheervalue_2025_multi_property_statement.bean")
-	golden, err := os.ReadFile(goldenPath)
+func TestParseErrors(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "sheervalue", "multi_prop", "sheervalue_2025_multi_property_statement.txt"))
 	if err != nil {
 		t.Fatal(err)
 	}
+	text := strings.Replace(string(fixture), "1/3/2025 206 Hoover", "2/30/2025 206 Hoover", 1)
+	text = strings.Replace(text, "Ending cash balance as of 8/7/2025", "Ending cash balance as of 8/77/2025", 1)
 
-	// Compare
-	if strings.TrimSpace(output) != strings.TrimSpace(string(golden)) {
-		t.Errorf("Output does not match golden file.\nGot:\n%s\n\nWant:\n%s", output, string(golden))
+	txs, err := sheervalue.NewParser().Parse(text)
+	var parseErrs parser.ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) != 2 {
+		t.Fatalf("Parse() error = %v, want 2 parse errors", err)
+	}
+	tests := []struct {
+		line  int
+		field string
+	}{
+		{line: 75, field: "2/30/2025"},
+		{line: 228, field: "8/77/2025"},
+	}
+	for i, tt := range tests {
+		got := parseErrs[i]
+		if got.Line != tt.line || !errors.Is(got, parser.ErrInvalidDate) || got.Text[got.Column-1:got.Column-1+len(tt.field)] != tt.field {
+			t.Errorf("error %d = %+v, want invalid date %q on line %d", i, got, tt.field, tt.line)
+		}
+	}
+	if len(txs) == 0 {
+		t.Errorf("Parse() returned no entries alongside the errors")
 	}
 }
@@ -0,0 +1,161 @@
+// internal/sheervalue/summary.go
+package sheervalue
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/jason-riddle/ledger-go/internal/normalize"
+	"github.com/jason-riddle/ledger-go/internal/parser"
+)
+
+// Summary tables, named by their letter-spaced headings with the spaces
+// removed. Both print one amount column per property and a final column
+// for all properties.
+const (
+	summaryByProperty = "Summarybyproperty"
+	incomeStatement   = "Incomestatement"
+)
+
+// allProperties is the heading of the last column of the summary tables.
+const allProperties = "All properties"
+
+// Summary by property adjustments: cash the property manager holds that is
+// not available to pay the owner.
+const (
+	depositsLabel = "Tenant security deposits and early payments"
+	reserveLabel  = "Property reserve"
+)
+
+var summaryColumnRe = regexp.MustCompile(`\s{2,}`)
+
+// ParseSummary reads the totals in the summary by property and income
+// statement tables, one total per property column.
+//
+// Summary by property rows are checked by category: income and expenses
+// per property, and owner draws for all properties only, since draws are
+// booked to a single equity account. Income statement rows are checked per
+// GL account and property through mapAccount.
+func (p *sheerValueParser) ParseSummary(ctx context.Context, text string) ([]parser.SummaryTotal, error) {
+	var totals []parser.SummaryTotal
+	var errs parser.ParseErrors
+	table := ""
+	// properties are the table's column headings; nil until the header
+	// line is read.
+	var properties []string
+	// addsCash is set by the income statement's Income and Expense groups.
+	addsCash := false
+
+	for i, line := range strings.Split(text, "\n") {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		switch heading := strings.Join(strings.Fields(line), ""); {
+		case strings.EqualFold(heading, summaryByProperty), strings.EqualFold(heading, incomeStatement):
+			table, properties = heading, nil
+			continue
+		case table == "":
+			continue
+		case heading == "":
+			if properties != nil {
+				table = ""
+			}
+			continue
+		}
+
+		fields := summaryColumnRe.Split(strings.TrimSpace(line), -1)
+		if properties == nil {
+			if fields[len(fields)-1] != allProperties {
+				table = ""
+				continue
+			}
+			properties = fields
+			continue
+		}
+		label := strings.TrimLeft(fields[0], "+–- ")
+		if len(fields) != len(properties)+1 {
+			// Group headings such as "+ Additions to cash" and "Expense".
+			switch label {
+			case "Income":
+				addsCash = true
+			case "Expense":
+				addsCash = false
+			}
+			continue
+		}
+
+		for col, property := range properties {
+			amount, err := normalize.Amount(fields[col+1])
+			if err != nil {
+				errs = append(errs, parser.NewParseError(institution, i+1, line, fields[col+1], err))
+				continue
+			}
+			total := parser.SummaryTotal{Label: label, Amount: amount, Line: i + 1}
+			if property != allProperties {
+				total.Property = normalize.PropertySlug(property)
+			}
+			account, negate := p.summaryAccount(table, label, total.Property, addsCash)
+			total.Account = account
+			if negate {
+				total.Amount = normalize.Negate(amount)
+			}
+			totals = append(totals, total)
+		}
+	}
+	return totals, errs.Err()
+}
+
+// summaryAccount returns the account a summary row totals for the property
+// slug ("" for all properties) and whether the statement prints it with the
+// opposite sign to the postings. The account is empty for rows with no
+// entries to compare.
+func (p *sheerValueParser) summaryAccount(table, label, property string, addsCash bool) (string, bool) {
+	switch {
+	case label == "Income" || strings.EqualFold(label, "Total income"):
+		return "Income", true
+	case label == "Expenses" || strings.EqualFold(label, "Total expenses"):
+		return "Expenses", false
+	case label == "Owner draws" && property == "":
+		return "Equity:Owner-Distributions", false
+	case table == incomeStatement && property != "" && label != "Net income":
+		// GL accounts booked to suspense, or not booked per property like
+		// owner draws, cannot be checked per property.
+		account, reason := p.mapAccount(label, "", property)
+		if reason != "" || !strings.HasSuffix(account, ":"+property) {
+			return "", false
+		}
+		return account, addsCash
+	default:
+		return "", false
+	}
+}
+
+// summaryBalances asserts the deposits and reserve each property holds on
+// date. Deposits include rent paid early, which the statement does not
+// separate from them.
+func summaryBalances(totals []parser.SummaryTotal, date string) []*parser.Transaction {
+	var balances []*parser.Transaction
+	for _, total := range totals {
+		if total.Property == "" {
+			continue
+		}
+		var account, amount string
+		switch total.Label {
+		case depositsLabel:
+			account, amount = parser.DepositAccount(total.Property), normalize.Negate(total.Amount)
+		case reserveLabel:
+			account, amount = parser.ReserveAccount(total.Property), total.Amount
+		default:
+			continue
+		}
+		balances = append(balances, &parser.Transaction{
+			Date:           date,
+			Directive:      "balance",
+			BalanceAccount: account,
+			BalanceAmount:  parser.Amount{Value: amount, Currency: "USD"},
+			Line:           total.Line,
+		})
+	}
+	return balances
+}
@@ -0,0 +1,150 @@
+// internal/sheervalue/summary_test.go
+package sheervalue_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jason-riddle/ledger-go/internal/parser"
+	"github.com/jason-riddle/ledger-go/internal/shared"
+	"github.com/jason-riddle/ledger-go/internal/sheervalue"
+)
+
+func TestParseSummary(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "sheervalue", "multi_prop", "sheervalue_2025_multi_property_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		// replace edits the fixture before parsing.
+		replace      [2]string
+		wantMismatch []string
+	}{
+		{name: "fixture"},
+		{
+			name:         "property income differs",
+			replace:      [2]string{"Income                                                 6,550.00", "Income                                                 6,500.00"},
+			wantMismatch: []string{"line 20: Income (Income, 206-Hoover-Ave): statement -6500.00, entries -6550.00"},
+		},
+		{
+			name:         "GL account differs",
+			replace:      [2]string{"Repairs                                                434.00", "Repairs                                                154.00"},
+			wantMismatch: []string{"line 53: Repairs (Expenses:Repairs:206-Hoover-Ave, 206-Hoover-Ave): statement 154.00, entries 434.00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := string(fixture)
+			if tt.replace[0] != "" {
+				if !strings.Contains(text, tt.replace[0]) {
+					t.Fatalf("fixture has no %q", tt.replace[0])
+				}
+				text = strings.Replace(text, tt.replace[0], tt.replace[1], 1)
+			}
+			p := sheervalue.NewParser()
+			txs, err := p.Parse(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			totals, err := p.(parser.Summarizer).ParseSummary(context.Background(), text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(totals) != 57 {
+				t.Errorf("ParseSummary() returned %d totals, want 57", len(totals))
+			}
+
+			err = shared.CheckSummary(txs, totals)
+			var mismatch *shared.SummaryMismatchError
+			if tt.wantMismatch == nil {
+				if err != nil {
+					t.Errorf("CheckSummary() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("CheckSummary() error = %v, want *SummaryMismatchError", err)
+			}
+			for _, want := range tt.wantMismatch {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("CheckSummary() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSummaryBalances(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "sheervalue", "multi_prop", "sheervalue_2025_multi_property_statement.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// reservePosted books the 4/9 late fee, with its wrapped "Income", to the
+	// property reserve instead.
+	reservePosted := [2]string{
+		"Late Fee      Unit 1 - George Mahara    by George Mahara                            138.00          14,338.00\n                                                      Income\n",
+		"Reserve       Unit 1 - George Mahara    by George Mahara                            138.00          14,338.00\n",
+	}
+	tests := []struct {
+		name string
+		// replace edits the fixture before parsing.
+		replace [][2]string
+		want    []string
+	}{
+		{
+			// The statement never posts to the deposit or reserve accounts.
+			name: "fixture",
+		},
+		{
+			name:    "reserve posted",
+			replace: [][2]string{reservePosted},
+			want:    []string{"2025-08-07 Assets:Property-Reserves:206-Hoover-Ave 0.00 line 30"},
+		},
+		{
+			name: "reserve held",
+			replace: [][2]string{
+				reservePosted,
+				{"Property reserve                                           0.00                  0.00              0.00", "Property reserve                                         500.00                  0.00            500.00"},
+			},
+			want: []string{"2025-08-07 Assets:Property-Reserves:206-Hoover-Ave 500.00 line 30"},
+		},
+		{
+			name:    "no ending cash balance",
+			replace: [][2]string{reservePosted, {"Ending cash balance as of 8/7/2025", "Ending cash balance"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := string(fixture)
+			for _, replace := range tt.replace {
+				if !strings.Contains(text, replace[0]) {
+					t.Fatalf("fixture has no %q", replace[0])
+				}
+				text = strings.Replace(text, replace[0], replace[1], 1)
+			}
+			txs, err := sheervalue.NewParser().Parse(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, tx := range txs {
+				if tx.Directive == "balance" && !strings.HasPrefix(tx.BalanceAccount, "Assets:Property-Management") {
+					got = append(got, fmt.Sprintf("%s %s %s line %d", tx.Date, tx.BalanceAccount, tx.BalanceAmount.Value, tx.Line))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("balances =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package sps_test
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jason-riddle/ledger-go/internal/sps"
 )
@@ -10,7 +11,8 @@ import (
 // ExampleNewParser demonstrates creating and using an SPS parser.
 func ExampleNewParser() {
 	parser := sps.NewParser()
-	text := `01/15 Mortgage Payment -500.00`
+	text := `Transaction Activity
+01/15 Mortgage Payment -500.00`
 
 	txs, err := parser.Parse(text)
 	if err != nil {
@@ -19,7 +21,7 @@ func ExampleNewParser() {
 	}
 
 	for _, tx := range txs {
-		fmt.Printf("%s * \"%s\" \"%s\" beangulp imported\n", tx.Date, tx.Payee, tx.Narration)
+		fmt.Printf("%s * \"%s\" \"%s\" %s\n", tx.Date, tx.Payee, tx.Narration, strings.Join(tx.Tags, " "))
 		for _, p := range tx.Postings {
 			fmt.Printf("  %s  %s %s\n", p.Account, p.Amount.Value, p.Amount.Currency)
 		}
@@ -27,7 +29,7 @@ func ExampleNewParser() {
 	}
 
 	// Output:
-	// 2024-01-15 * "SPS Mortgage" "Mortgage Payment" beangulp imported
-	//   Liabilities:Mortgages:SPS  -500.00 USD
-	//   Expenses:Mortgage-Interest:SPS  500.00 USD
+	// 2024-01-15 * "SPS Mortgage" "Mortgage Payment" #imported #mortgage
+	//   Equity:Owner-Contributions:Cash-Infusion  -500.00 USD
+	//   Liabilities:Mortgages:SPS  500.00 USD
 }
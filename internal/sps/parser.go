@@ -2,10 +2,13 @@
 package sps
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/jason-riddle/ledger-go/internal/normalize"
 	"github.com/jason-riddle/ledger-go/internal/parser"
 )
 
@@ -18,51 +21,341 @@ func NewParser() parser.Parser {
 
 // Parse extracts transactions from SPS statement text.
 func (p *spsParser) Parse(text string) ([]*parser.Transaction, error) {
-	var txs []*parser.Transaction
+	return p.ParseContext(context.Background(), text)
+}
+
+// ParseContext extracts transactions from SPS statement text, checking ctx
+// after scanning the text and before each matched entry.
+func (p *spsParser) ParseContext(ctx context.Context, text string) ([]*parser.Transaction, error) {
+	txs, _, err := p.ParseCoverage(ctx, text)
+	return txs, err
+}
 
-	// Simple regex for SPS transaction lines (adapt based on actual format)
-	re := regexp.MustCompile(`(\d{2}/\d{2})\s+(.+?)\s+(-?\d+\.\d{2})`)
-	matches := re.FindAllStringSubmatch(text, -1)
+// institution names the parser in errors.
+const institution = "sps"
 
-	for _, match := range matches {
-		dateStr := match[1]
-		desc := strings.TrimSpace(match[2])
-		amountStr := match[3]
+// activitySection is the section transactions are read from; it ends with
+// the ENDING BALANCE row.
+const activitySection = "Transaction Activity"
+
+// defaultPeriodEnd dates rows when the statement has no activity period.
+var defaultPeriodEnd = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+var activityPeriodRe = regexp.MustCompile(`Transaction Activity\s*\(\s*[\d/ ]+\s+to\s+(\d{1,2}\s*/\s*\d{1,2}\s*/\s*\d{4})\s*\)`)
+
+// activityPeriodEnd returns the last day of the statement's transaction
+// activity period, which rows dated MM/DD fall on or before.
+func activityPeriodEnd(text string) time.Time {
+	if match := activityPeriodRe.FindStringSubmatch(text); match != nil {
+		if date, err := normalize.Date(match[1]); err == nil {
+			end, _ := time.Parse(normalize.DateLayout, date)
+			return end
+		}
+	}
+	return defaultPeriodEnd
+}
+
+// balanceRowRe matches the BEG BALANCE and ENDING BALANCE rows, which hold
+// what the borrower owes in each column on the date.
+var balanceRowRe = regexp.MustCompile(`^\s*(\d{2}/\d{2})\s+(?:BEG|ENDING) BALANCE\b(.*)$`)
+
+// transactionRe matches an activity row: an MM/DD date, the description
+// and one or more amount columns, the last of which is the total. Amounts
+// may be signed, like -500.00, or in parentheses, like (1,178.63).
+var transactionRe = regexp.MustCompile(`^\s*(\d{2}/\d{2})\s+(.*?\S)((?:\s+\(?-?[\d,]*\d\.\d{2}\)?)+)\s*$`)
+
+var propertyAddressRe = regexp.MustCompile(`Property Address\s+(\S.*?)\s*$`)
+
+// defaultProperty names the property accounts when the statement has no
+// property address.
+const defaultProperty = "SPS"
+
+// fundingAccount is where the payments and deposits the borrower sends
+// the servicer come from.
+const fundingAccount = "Equity:Owner-Contributions:Cash-Infusion"
+
+// activityColumn is an amount column of the activity table before the
+// total. Its amounts are the change in what the borrower owes, so a
+// payment is negative; the column's account gets the opposite amount.
+type activityColumn struct {
+	name string
+	// account is suffixed with the property; "" books to suspense.
+	account string
+}
+
+var activityColumns = []activityColumn{
+	{name: "Principal", account: "Liabilities:Mortgages"},
+	{name: "Interest", account: "Expenses:Mortgage-Interest"},
+	{name: "Escrow", account: "Assets:Escrow:Taxes---Insurance"},
+	{name: "Late Charges"},
+	{name: "Other Fees"},
+}
+
+// rowMemos names the servicer's activity codes in narrations.
+var rowMemos = map[string]string{
+	"PAYMENT":         "Mortgage Payment",
+	"HAZARD INS":      "Insurance Payment",
+	"SPECIAL DEPOSIT": "Special Deposit",
+}
 
-		// Convert MM/DD to YYYY-MM-DD (assume current year)
-		dateStr = "2024-" + dateStr[:2] + "-" + dateStr[3:]
+// ParseCoverage is ParseContext that also classifies every line of the
+// transaction activity section as consumed, ignored or unrecognized.
+func (p *spsParser) ParseCoverage(ctx context.Context, text string) ([]*parser.Transaction, *parser.Coverage, error) {
+	var txs []*parser.Transaction
+	var errs parser.ParseErrors
+	lines := strings.Split(text, "\n")
 
-		payee := p.mapPayee(desc)
-		account := p.mapAccount(desc)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	periodEnd := activityPeriodEnd(text)
+	property := propertySlug(lines)
+	inActivity := activityLines(lines)
 
-		var postings []parser.Posting
-		if strings.HasPrefix(amountStr, "-") {
-			// Expense
-			amount := strings.TrimPrefix(amountStr, "-")
-			postings = []parser.Posting{
-				{Account: account, Amount: parser.Amount{Value: amount, Currency: "USD"}},
-				{Account: "Liabilities:Mortgages:SPS", Amount: parser.Amount{Value: fmt.Sprintf("-%s", amount), Currency: "USD"}},
+	for i, line := range lines {
+		if !inActivity[i] {
+			continue
+		}
+		if match := balanceRowRe.FindStringSubmatch(line); match != nil {
+			balances, err := balanceDirectives(i+1, line, match, periodEnd, property)
+			if err != nil {
+				errs = append(errs, err)
 			}
-		} else {
-			// Income or other
-			postings = []parser.Posting{
-				{Account: "Liabilities:Mortgages:SPS", Amount: parser.Amount{Value: fmt.Sprintf("-%s", amountStr), Currency: "USD"}},
-				{Account: account, Amount: parser.Amount{Value: amountStr, Currency: "USD"}},
+			txs = append(txs, balances...)
+			continue
+		}
+		match := transactionRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		lineNum := i + 1
+		desc := strings.TrimSpace(match[2])
+		fields := strings.Fields(match[3])
+		amounts := make([]string, len(fields))
+		var invalid bool
+		for j, field := range fields {
+			amount, err := normalize.Amount(field)
+			if err != nil {
+				errs = append(errs, parser.NewParseError(institution, lineNum, line, field, err))
+				invalid = true
+				break
 			}
+			amounts[j] = amount
+		}
+		if invalid {
+			continue
+		}
+
+		// Convert MM/DD to YYYY-MM-DD in the year of the activity period
+		dateStr, err := normalize.InferDate(match[1], periodEnd)
+		if err != nil {
+			errs = append(errs, parser.NewParseError(institution, lineNum, line, match[1], err))
+			continue
 		}
+
+		// The last column is the row's total; rows without every column
+		// are booked against the principal.
+		total := amounts[len(amounts)-1]
+		var columns []string
+		if len(amounts) == len(activityColumns)+1 {
+			columns = amounts[:len(activityColumns)]
+		}
+		postings := p.postings(desc, columns, total, property)
 		parser.OrderPostingsBySign(postings)
 
 		tx := &parser.Transaction{
 			Date:      dateStr,
-			Payee:     payee,
-			Narration: desc,
-			Tags:      []string{"beangulp", "imported"},
+			Payee:     p.mapPayee(desc),
+			Narration: narration(desc, columns, total),
+			Tags:      []string{"#imported", "#mortgage"},
 			Postings:  postings,
+			Line:      lineNum,
+		}
+		for _, posting := range postings {
+			if posting.Account == parser.SuspenseAccount {
+				tx.MarkForReview(parser.UnmatchedReason)
+				break
+			}
 		}
 		txs = append(txs, tx)
 	}
 
-	return txs, nil
+	return txs, classifyLines(lines, txs, errs), errs.Err()
+}
+
+// postings books each column of an activity row to its account and the
+// total to where the money came from or went: a negative total is a
+// payment or deposit from the borrower, and a positive one is paid out of
+// the loan or escrow to the account the description maps to.
+func (p *spsParser) postings(desc string, columns []string, total, property string) []parser.Posting {
+	var postings []parser.Posting
+	add := func(account, amount string) {
+		postings = append(postings, parser.Posting{Account: account, Amount: parser.Amount{Value: amount, Currency: "USD"}})
+	}
+	if columns == nil {
+		add("Liabilities:Mortgages:"+property, normalize.Negate(total))
+	}
+	for i, amount := range columns {
+		if isZero(amount) {
+			continue
+		}
+		account := parser.SuspenseAccount
+		if activityColumns[i].account != "" {
+			account = activityColumns[i].account + ":" + property
+		}
+		add(account, normalize.Negate(amount))
+	}
+	switch {
+	case isZero(total):
+	case strings.HasPrefix(total, "-"):
+		add(fundingAccount, total)
+	default:
+		add(p.mapAccount(desc, property), total)
+	}
+	return postings
+}
+
+// balanceDirectives asserts the balance of each column's account on the
+// date of a balance row; rows without every column assert nothing. The
+// column's account holds the opposite of what the borrower owes, as in
+// postings.
+func balanceDirectives(lineNum int, line string, match []string, periodEnd time.Time, property string) ([]*parser.Transaction, *parser.ParseError) {
+	fields := strings.Fields(match[2])
+	if len(fields) != len(activityColumns)+1 {
+		return nil, nil
+	}
+	dateStr, err := normalize.InferDate(match[1], periodEnd)
+	if err != nil {
+		return nil, parser.NewParseError(institution, lineNum, line, match[1], err)
+	}
+	var balances []*parser.Transaction
+	for i, column := range activityColumns {
+		amount, err := normalize.Amount(fields[i])
+		if err != nil {
+			return nil, parser.NewParseError(institution, lineNum, line, fields[i], err)
+		}
+		if column.account == "" {
+			continue
+		}
+		balances = append(balances, &parser.Transaction{
+			Date:           dateStr,
+			Directive:      "balance",
+			BalanceAccount: column.account + ":" + property,
+			BalanceAmount:  parser.Amount{Value: normalize.Negate(amount), Currency: "USD"},
+			Line:           lineNum,
+		})
+	}
+	return balances, nil
+}
+
+// narration describes an activity row. Known activity codes become a memo
+// listing the row's nonzero columns, or its total when money was paid out.
+func narration(desc string, columns []string, total string) string {
+	memo, ok := rowMemos[strings.ToUpper(desc)]
+	if !ok {
+		return desc
+	}
+	var parts []string
+	for i, amount := range columns {
+		if !isZero(amount) {
+			parts = append(parts, fmt.Sprintf("%s: $%s", activityColumns[i].name, strings.TrimPrefix(amount, "-")))
+		}
+	}
+	switch {
+	case len(parts) > 1:
+		return "Memo: " + memo + " - " + strings.Join(parts, ", ")
+	case !isZero(total) && !strings.HasPrefix(total, "-"):
+		return "Memo: " + memo + " - Total: $" + total
+	default:
+		return "Memo: " + memo
+	}
+}
+
+// propertySlug names the property accounts after the statement's property
+// address, which SPS prints in capitals.
+func propertySlug(lines []string) string {
+	for _, line := range lines {
+		if match := propertyAddressRe.FindStringSubmatch(line); match != nil {
+			words := strings.Fields(strings.ToLower(match[1]))
+			for i, word := range words {
+				words[i] = strings.ToUpper(word[:1]) + word[1:]
+			}
+			return normalize.PropertySlug(strings.Join(words, " "))
+		}
+	}
+	return defaultProperty
+}
+
+// activityLines reports which lines are inside the transaction activity
+// section: after its heading, up to and including the ENDING BALANCE row.
+func activityLines(lines []string) []bool {
+	inActivity := make([]bool, len(lines))
+	in := false
+	for i, line := range lines {
+		inActivity[i] = in
+		if strings.Contains(line, activitySection) {
+			in = true
+		}
+		if strings.Contains(line, "ENDING BALANCE") {
+			in = false
+		}
+	}
+	return inActivity
+}
+
+func isZero(amount string) bool {
+	return strings.Trim(amount, "-0.") == ""
+}
+
+// classifyLines builds the coverage report: lines that produced a
+// transaction or balance are consumed, lines with parse errors are unrecognized, and
+// the rest of the activity section may only
+// hold blank lines, balance rows and text without amounts, like the wrapped
+// column headers.
+func classifyLines(lines []string, txs []*parser.Transaction, errs parser.ParseErrors) *parser.Coverage {
+	consumed := map[int]string{}
+	for _, tx := range txs {
+		consumed[tx.Line] = "transaction"
+		if tx.Directive == "balance" {
+			consumed[tx.Line] = "balance"
+		}
+	}
+	failed := map[int]bool{}
+	for _, err := range errs {
+		failed[err.Line] = true
+	}
+	coverage := &parser.Coverage{}
+	inActivity := activityLines(lines)
+	for i, line := range lines {
+		heading := strings.Contains(line, activitySection)
+		section := ""
+		if inActivity[i] || heading {
+			section = activitySection
+		}
+		switch {
+		case consumed[i+1] != "":
+			coverage.Add(i+1, line, section, parser.LineConsumed, consumed[i+1])
+		case failed[i+1]:
+			coverage.Add(i+1, line, section, parser.LineUnrecognized, "parse error")
+		case heading:
+			coverage.Add(i+1, line, section, parser.LineIgnored, "section heading")
+		case !inActivity[i]:
+			coverage.Add(i+1, line, section, parser.LineOutside, "")
+		case strings.TrimSpace(line) == "":
+			coverage.Add(i+1, line, section, parser.LineIgnored, "blank")
+		case balanceRowRe.MatchString(line):
+			// Balance rows without every column assert nothing.
+			coverage.Add(i+1, line, section, parser.LineIgnored, "balance row")
+		case !parser.HasAmount(line):
+			coverage.Add(i+1, line, section, parser.LineIgnored, "text without amounts")
+		default:
+			coverage.Add(i+1, line, section, parser.LineUnrecognized, "")
+		}
+	}
+	return coverage
 }
 
 // mapPayee maps description to payee name
@@ -71,16 +364,21 @@ func (p *spsParser) mapPayee(desc string) string {
 	case strings.Contains(desc, "Mortgage Payment"):
 		return "SPS Mortgage"
 	default:
-		return desc
+		return "SPS Mortgage Servicing"
 	}
 }
 
-// mapAccount maps description to account
-func (p *spsParser) mapAccount(desc string) string {
+// mapAccount maps the description of a row paid out of the loan or escrow
+// to the property's expense account, or to the suspense account when no
+// rule matches.
+func (p *spsParser) mapAccount(desc, property string) string {
+	upper := strings.ToUpper(desc)
 	switch {
-	case strings.Contains(desc, "Mortgage Payment"):
-		return "Expenses:Mortgage-Interest:SPS"
+	case strings.Contains(upper, "INS"):
+		return "Expenses:Insurance:" + property
+	case strings.Contains(upper, "TAX"):
+		return "Expenses:Property-Taxes:" + property
 	default:
-		return "Expenses:Other"
+		return parser.SuspenseAccount
 	}
 }